@@ -0,0 +1,222 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// Command archive creates, extracts and lists the contents of tar and zip
+// archives.
+//
+// It is run by the archive module inside a dedicated container so
+// archives and their contents are streamed from mounts instead of being
+// copied through the module runtime.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"dagger/archive/internal/archive"
+)
+
+const (
+	formatAuto = "auto"
+	formatTar  = "tar"
+	formatZip  = "zip"
+)
+
+type patterns []string
+
+func (p *patterns) String() string {
+	return strings.Join(*p, ",")
+}
+
+func (p *patterns) Set(s string) error {
+	*p = append(*p, s)
+	return nil
+}
+
+func main() {
+	err := run(context.Background(), os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: archive <create|extract|list> [flags]")
+	}
+
+	switch args[0] {
+	case "create":
+		return create(ctx, args[1:])
+	case "extract":
+		return extract(ctx, args[1:])
+	case "list":
+		return list(args[1:])
+	default:
+		return fmt.Errorf("unknown command: %s", args[0])
+	}
+}
+
+type archiveFlags struct {
+	format      string
+	compression string
+}
+
+func (f *archiveFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.format, "format", formatAuto, "archive format: auto, tar or zip")
+	fs.StringVar(&f.compression, "compression", archive.CompressionAuto, "tar compression: auto, none, gzip, zstd, xz or bzip2")
+}
+
+// resolve detects the format of the archive if needed and normalizes
+// the compression to the values understood by the archive package.
+func (f *archiveFlags) resolve(filename string) error {
+	if f.compression == "none" {
+		f.compression = archive.CompressionNone
+	}
+
+	if f.format != formatAuto {
+		return nil
+	}
+
+	isZip, err := archive.IsZip(filename)
+	if err != nil {
+		return err
+	}
+
+	f.format = formatTar
+	if isZip {
+		f.format = formatZip
+	}
+	return nil
+}
+
+// create archives the contents of a directory, the format must be given
+// since there is no archive to detect it from.
+func create(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("create", flag.ContinueOnError)
+
+	format := fs.String("format", formatTar, "archive format: tar or zip")
+	compression := fs.String("compression", "none", "tar compression: none, gzip or zstd")
+	reproducible := fs.Bool("reproducible", false, "normalize entry ownership and modification times")
+	mtime := fs.Int64("mtime", 0, "modification time of every entry, in seconds since the Unix epoch, when reproducible")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: archive create [flags] <dir> <archive>")
+	}
+
+	dir, filename := fs.Arg(0), fs.Arg(1)
+
+	if *compression == "none" {
+		*compression = archive.CompressionNone
+	}
+
+	opts := archive.CreateOptions{
+		Compression:  *compression,
+		Reproducible: *reproducible,
+		ModTime:      time.Unix(*mtime, 0),
+	}
+
+	switch *format {
+	case formatTar:
+		return archive.CreateTar(ctx, dir, filename, opts)
+	case formatZip:
+		return archive.CreateZip(ctx, dir, filename, opts)
+	default:
+		return fmt.Errorf("unsupported archive format: %s", *format)
+	}
+}
+
+func extract(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("extract", flag.ContinueOnError)
+
+	var af archiveFlags
+	af.register(fs)
+
+	stripComponents := fs.Int("strip-components", 0, "number of leading path elements to remove from entry names")
+
+	var include patterns
+	fs.Var(&include, "include", "only extract entries matching this glob, may be repeated")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: archive extract [flags] <archive> <out>")
+	}
+
+	filename, out := fs.Arg(0), fs.Arg(1)
+
+	err = af.resolve(filename)
+	if err != nil {
+		return err
+	}
+
+	opts := archive.ExtractOptions{
+		StripComponents: *stripComponents,
+		Include:         include,
+	}
+
+	switch af.format {
+	case formatTar:
+		return archive.ExtractTar(ctx, filename, out, af.compression, opts)
+	case formatZip:
+		return archive.ExtractZip(ctx, filename, out, opts)
+	default:
+		return fmt.Errorf("unsupported archive format: %s", af.format)
+	}
+}
+
+// list writes the entries of an archive to stdout as a JSON array.
+func list(args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+
+	var af archiveFlags
+	af.register(fs)
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: archive list [flags] <archive>")
+	}
+
+	filename := fs.Arg(0)
+
+	err = af.resolve(filename)
+	if err != nil {
+		return err
+	}
+
+	var entries []archive.Entry
+	switch af.format {
+	case formatTar:
+		entries, err = archive.ListTar(filename, af.compression)
+	case formatZip:
+		entries, err = archive.ListZip(filename)
+	default:
+		return fmt.Errorf("unsupported archive format: %s", af.format)
+	}
+	if err != nil {
+		return err
+	}
+
+	if entries == nil {
+		entries = []archive.Entry{}
+	}
+
+	return json.NewEncoder(os.Stdout).Encode(entries)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"dagger/archive/internal/dagger"
+)
+
+type createArgs struct {
+	format       string
+	name         string
+	include      []string
+	exclude      []string
+	compression  Compression
+	reproducible bool
+	mtime        int
+}
+
+// create archives the directory within the tool container. Only the
+// filtered contents are mounted, so they are never copied through the
+// module runtime.
+func create(dir *dagger.Directory, args createArgs) (*dagger.File, error) {
+	err := validateName(args.name)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := dag.Directory().WithDirectory(".", dir, dagger.DirectoryWithDirectoryOpts{
+		Include: args.include,
+		Exclude: args.exclude,
+	})
+
+	cmd := []string{"archive", "create", "-format", args.format, "-mtime", strconv.Itoa(args.mtime)}
+	if args.compression != "" {
+		cmd = append(cmd, "-compression", string(args.compression))
+	}
+	if args.reproducible {
+		cmd = append(cmd, "-reproducible")
+	}
+	filename := path.Join("/out", args.name)
+	cmd = append(cmd, "/in", filename)
+
+	f := tool().
+		WithMountedDirectory("/in", filtered).
+		WithDirectory("/out", dag.Directory()).
+		WithExec(cmd).
+		File(filename)
+
+	return f, nil
+}
+
+// validateName rejects archive names which would be written outside the
+// output directory.
+func validateName(name string) error {
+	if name == "" || name == "." || strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return fmt.Errorf("invalid archive name: %q", name)
+	}
+	return nil
+}
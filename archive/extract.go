@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"path"
+	"strconv"
+	"strings"
+
+	"dagger/archive/internal/dagger"
+)
+
+// goImage is the toolchain the archive command is built with, matching
+// the go directive of this module.
+const goImage = "golang:1.24.4-alpine"
+
+// tool builds the archive command from this module's source into an
+// otherwise empty container. Archives and their contents are mounted into
+// it rather than exported to the module runtime, so neither creating nor
+// extracting an archive consumes the module's scratch space or shares an
+// output path between calls.
+//
+// Only the sources of the command are mounted and the image is pinned, so
+// the build is cached by the engine and runs once rather than per call.
+func tool() *dagger.Container {
+	src := dag.Directory().WithDirectory(".", dag.CurrentModule().Source(), dagger.DirectoryWithDirectoryOpts{
+		Include: []string{"go.mod", "go.sum", "cmd/archive/**", "internal/archive/**"},
+		Exclude: []string{"**/*_test.go", "**/testdata/**"},
+	})
+
+	bin := dag.Container().
+		From(goImage).
+		WithMountedCache("/root/.cache/go-build", dag.CacheVolume("github.com/z5labs/daggerverse/archive:build")).
+		WithMountedCache("/go/pkg/mod", dag.CacheVolume("github.com/z5labs/daggerverse/archive:mod")).
+		WithEnvVariable("CGO_ENABLED", "0").
+		WithMountedDirectory("/src", src).
+		WithWorkdir("/src").
+		WithExec([]string{"go", "build", "-trimpath", "-o", "/archive", "./cmd/archive"}).
+		File("/archive")
+
+	return dag.Container().WithFile("/usr/local/bin/archive", bin)
+}
+
+// mountArchive mounts the file into the tool container and returns the
+// path it was mounted at. The original name is kept so compression can
+// be detected from the extension when the contents alone are not enough.
+func mountArchive(ctx context.Context, file *dagger.File) (*dagger.Container, string, error) {
+	name, err := file.Name(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if name == "" {
+		name = "archive"
+	}
+	filename := path.Join("/in", path.Base(name))
+
+	return tool().WithMountedFile(filename, file), filename, nil
+}
+
+type extractArgs struct {
+	format          string
+	compression     Compression
+	stripComponents int
+	include         []string
+}
+
+func extract(ctx context.Context, file *dagger.File, args extractArgs) (*dagger.Directory, error) {
+	ctr, filename, err := mountArchive(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := []string{"archive", "extract", "-format", args.format, "-strip-components", strconv.Itoa(args.stripComponents)}
+	if args.compression != "" {
+		cmd = append(cmd, "-compression", string(args.compression))
+	}
+	for _, pattern := range args.include {
+		cmd = append(cmd, "-include", pattern)
+	}
+	cmd = append(cmd, filename, "/out")
+
+	dir := ctr.
+		WithExec(cmd).
+		Directory("/out")
+
+	return dir, nil
+}
+
+// extractFile extracts a single entry, after stripping, by escaping any
+// glob syntax in its path so it can be used as an include pattern.
+func extractFile(ctx context.Context, file *dagger.File, args extractArgs, name string) (*dagger.File, error) {
+	name = path.Clean(strings.TrimPrefix(name, "/"))
+
+	var pattern strings.Builder
+	for _, r := range name {
+		if strings.ContainsRune(`*?[\`, r) {
+			pattern.WriteRune('\\')
+		}
+		pattern.WriteRune(r)
+	}
+	args.include = []string{pattern.String()}
+
+	dir, err := extract(ctx, file, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return dir.File(name), nil
+}
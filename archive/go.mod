@@ -5,6 +5,8 @@ go 1.24.4
 require (
 	github.com/99designs/gqlgen v0.17.75
 	github.com/Khan/genqlient v0.8.1
+	github.com/klauspost/compress v1.18.0
+	github.com/ulikunitz/xz v0.5.17
 	github.com/vektah/gqlparser/v2 v2.5.28
 	github.com/z5labs/sdk-go v0.2.0
 	go.opentelemetry.io/otel v1.36.0
@@ -0,0 +1,32 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package archive
+
+import "time"
+
+const (
+	CompressionNone  = ""
+	CompressionGzip  = "gzip"
+	CompressionZstd  = "zstd"
+	CompressionXz    = "xz"
+	CompressionBzip2 = "bzip2"
+
+	// Only valid when extracting, detects the compression from the
+	// archive contents or filename.
+	CompressionAuto = "auto"
+)
+
+type CreateOptions struct {
+	// Compression applied to the archive, only supported for tar.
+	Compression string
+
+	// Normalize entry ownership and modification times so the same
+	// directory contents always produce a byte-for-byte identical archive.
+	Reproducible bool
+
+	// Modification time assigned to every entry when Reproducible is set.
+	ModTime time.Time
+}
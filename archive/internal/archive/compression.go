@@ -0,0 +1,137 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+var magicNumbers = []struct {
+	compression string
+	magic       []byte
+
+	// Optionally validates the header further when the magic number
+	// alone is too short to be distinctive.
+	valid func(header []byte) bool
+}{
+	{compression: CompressionGzip, magic: []byte{0x1f, 0x8b}},
+	{compression: CompressionZstd, magic: []byte{0x28, 0xb5, 0x2f, 0xfd}},
+	{compression: CompressionXz, magic: []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}},
+	{compression: CompressionBzip2, magic: []byte{'B', 'Z', 'h'}, valid: validBzip2},
+}
+
+// validBzip2 requires the block size, '1' to '9', to follow the magic
+// number so a tar whose first entry name starts with BZh is not mistaken
+// for a bzip2 stream.
+func validBzip2(header []byte) bool {
+	return len(header) > 3 && header[3] >= '1' && header[3] <= '9'
+}
+
+var extensions = []struct {
+	compression string
+	suffixes    []string
+}{
+	{compression: CompressionGzip, suffixes: []string{".gz", ".tgz"}},
+	{compression: CompressionZstd, suffixes: []string{".zst", ".tzst"}},
+	{compression: CompressionXz, suffixes: []string{".xz", ".txz"}},
+	{compression: CompressionBzip2, suffixes: []string{".bz2", ".tbz2", ".tbz"}},
+}
+
+// DetectCompression returns the compression of a file given its leading
+// bytes. Magic numbers take precedence since downloaded files are often
+// renamed, falling back to the filename extension.
+func DetectCompression(header []byte, filename string) string {
+	for _, m := range magicNumbers {
+		if bytes.HasPrefix(header, m.magic) && (m.valid == nil || m.valid(header)) {
+			return m.compression
+		}
+	}
+
+	filename = strings.ToLower(filename)
+	for _, ext := range extensions {
+		for _, suffix := range ext.suffixes {
+			if strings.HasSuffix(filename, suffix) {
+				return ext.compression
+			}
+		}
+	}
+
+	return CompressionNone
+}
+
+// IsZip reports whether the file starts with a zip local file header, or
+// the end of central directory record in the case of an empty archive.
+func IsZip(filename string) (bool, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	header := make([]byte, 4)
+	_, err = io.ReadFull(f, header)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(header, []byte("PK\x03\x04")) || bytes.Equal(header, []byte("PK\x05\x06")), nil
+}
+
+type zstdReader struct {
+	*zstd.Decoder
+}
+
+func (r zstdReader) Close() error {
+	r.Decoder.Close()
+	return nil
+}
+
+// decompress wraps r with a reader for the given compression, detecting
+// it first if the compression is CompressionAuto.
+func decompress(r *bufio.Reader, compression, filename string) (io.ReadCloser, error) {
+	if compression == CompressionAuto {
+		// Peek returns what it could read alongside an error for short
+		// files which are then detected, or not, from what was read.
+		header, _ := r.Peek(6)
+		compression = DetectCompression(header, filename)
+	}
+
+	switch compression {
+	case CompressionNone:
+		return io.NopCloser(r), nil
+	case CompressionGzip:
+		return gzip.NewReader(r)
+	case CompressionZstd:
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return zstdReader{Decoder: zr}, nil
+	case CompressionXz:
+		xr, err := xz.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(xr), nil
+	case CompressionBzip2:
+		return io.NopCloser(bzip2.NewReader(r)), nil
+	default:
+		return nil, fmt.Errorf("unsupported tar compression: %s", compression)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestDetectCompression(t *testing.T) {
+	bzhTar := tarBytes(t, []entry{{name: "BZh.txt", mode: 0o644, body: "hello"}})
+
+	testCases := []struct {
+		name     string
+		header   []byte
+		filename string
+		expected string
+	}{
+		{
+			name:     "gzip",
+			header:   []byte{0x1f, 0x8b, 0x08, 0x00},
+			filename: "archive",
+			expected: CompressionGzip,
+		},
+		{
+			name:     "zstd",
+			header:   []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00},
+			filename: "archive",
+			expected: CompressionZstd,
+		},
+		{
+			name:     "xz",
+			header:   []byte{0xfd, '7', 'z', 'X', 'Z', 0x00},
+			filename: "archive",
+			expected: CompressionXz,
+		},
+		{
+			name:     "bzip2",
+			header:   []byte("BZh91AY&SY"),
+			filename: "archive",
+			expected: CompressionBzip2,
+		},
+		{
+			name:     "bzip2 without block size",
+			header:   []byte("BZh0"),
+			filename: "archive",
+			expected: CompressionNone,
+		},
+		{
+			name:     "tar with entry name starting with BZh",
+			header:   bzhTar[:6],
+			filename: "archive.tar",
+			expected: CompressionNone,
+		},
+		{
+			name:     "magic number takes precedence over extension",
+			header:   []byte{0x1f, 0x8b, 0x08, 0x00},
+			filename: "archive.tar.zst",
+			expected: CompressionGzip,
+		},
+		{
+			name:     "extension",
+			header:   []byte("plain"),
+			filename: "archive.TBZ2",
+			expected: CompressionBzip2,
+		},
+		{
+			name:     "short header",
+			header:   []byte("BZ"),
+			filename: "archive",
+			expected: CompressionNone,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := DetectCompression(tc.header, tc.filename)
+			if actual != tc.expected {
+				t.Errorf("expected %q but received: %q", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDecompressAutoTarWithBzhEntry(t *testing.T) {
+	b := tarBytes(t, []entry{{name: "BZh.txt", mode: 0o644, body: "hello"}})
+
+	rc, err := decompress(bufio.NewReader(bytes.NewReader(b)), CompressionAuto, "archive.tar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+
+	actual, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(actual, b) {
+		t.Error("expected tar to be read without decompression")
+	}
+}
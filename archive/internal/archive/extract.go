@@ -0,0 +1,335 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/z5labs/sdk-go/try"
+)
+
+// ErrUnsafePath is returned when an archive entry would be written, or
+// a link would point, outside of the output directory.
+var ErrUnsafePath = errors.New("archive entry escapes output directory")
+
+type ExtractOptions struct {
+	// Number of leading path elements to remove from entry names.
+	// Entries with fewer path elements are skipped.
+	StripComponents int
+
+	// Only extract entries, after stripping, matching at least one of these
+	// patterns. A pattern matching a directory includes everything within it.
+	Include []string
+}
+
+type dirEntry struct {
+	path    string
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+// extractor writes archive entries to an output directory, refusing any
+// entry which would escape it either directly or by traversing a symlink.
+type extractor struct {
+	out  string
+	opts ExtractOptions
+	dirs []dirEntry
+}
+
+func newExtractor(out string, opts ExtractOptions) (*extractor, error) {
+	err := os.MkdirAll(out, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &extractor{
+		out:  out,
+		opts: opts,
+	}
+	return e, nil
+}
+
+// name sanitizes and strips an entry name. An empty name means
+// the entry should be skipped.
+func (e *extractor) name(name string) (string, error) {
+	name, err := cleanName(name)
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		return "", nil
+	}
+
+	parts := strings.Split(name, "/")
+	if len(parts) <= e.opts.StripComponents {
+		return "", nil
+	}
+	name = path.Join(parts[e.opts.StripComponents:]...)
+
+	if !e.included(name) {
+		return "", nil
+	}
+
+	return name, nil
+}
+
+func (e *extractor) included(name string) bool {
+	if len(e.opts.Include) == 0 {
+		return true
+	}
+
+	for p := name; p != "."; p = path.Dir(p) {
+		for _, pattern := range e.opts.Include {
+			ok, _ := path.Match(pattern, p)
+			if ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// cleanName rejects absolute names and names containing enough ".."
+// elements to escape the archive root. Backslashes are treated as path
+// separators since archives created on Windows commonly contain them.
+func cleanName(name string) (string, error) {
+	original := name
+
+	name = strings.ReplaceAll(name, `\`, "/")
+	if path.IsAbs(name) || filepath.VolumeName(name) != "" || (len(name) >= 2 && name[1] == ':') {
+		return "", fmt.Errorf("%w: absolute path: %s", ErrUnsafePath, original)
+	}
+
+	name = path.Clean(name)
+	if name == ".." || strings.HasPrefix(name, "../") {
+		return "", fmt.Errorf("%w: %s", ErrUnsafePath, original)
+	}
+	if name == "." {
+		return "", nil
+	}
+
+	return name, nil
+}
+
+// path returns the location of an entry within the output directory after
+// creating its parent directories. Existing symlinks are never traversed
+// since they could redirect the write outside of the output directory.
+func (e *extractor) path(name string) (string, error) {
+	err := e.mkdirAll(path.Dir(name))
+	if err != nil {
+		return "", err
+	}
+
+	p := filepath.Join(e.out, filepath.FromSlash(name))
+
+	info, err := os.Lstat(p)
+	if err == nil && info.Mode()&fs.ModeSymlink != 0 {
+		err = os.Remove(p)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return p, nil
+}
+
+func (e *extractor) mkdirAll(name string) error {
+	if name == "." {
+		return nil
+	}
+
+	p := e.out
+	for _, elem := range strings.Split(name, "/") {
+		p = filepath.Join(p, elem)
+
+		info, err := os.Lstat(p)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			err = os.Mkdir(p, 0o755)
+			if err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		case info.Mode()&fs.ModeSymlink != 0:
+			return fmt.Errorf("%w: path traverses a symlink: %s", ErrUnsafePath, name)
+		case !info.IsDir():
+			return fmt.Errorf("not a directory: %s", name)
+		}
+	}
+
+	return nil
+}
+
+func (e *extractor) dir(name string, mode fs.FileMode, modTime time.Time) error {
+	p, err := e.path(name)
+	if err != nil {
+		return err
+	}
+
+	err = e.mkdirAll(name)
+	if err != nil {
+		return err
+	}
+
+	// Permissions are applied once everything has been extracted so a
+	// read-only directory does not prevent writing its contents.
+	e.dirs = append(e.dirs, dirEntry{
+		path:    p,
+		mode:    mode.Perm(),
+		modTime: modTime,
+	})
+	return nil
+}
+
+func (e *extractor) file(name string, mode fs.FileMode, modTime time.Time, r io.Reader) error {
+	p, err := e.path(name)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	err = writeFile(f, r)
+	if err != nil {
+		return err
+	}
+
+	// Chmod explicitly since the mode given to OpenFile is subject to umask.
+	err = os.Chmod(p, mode.Perm())
+	if err != nil {
+		return err
+	}
+
+	return os.Chtimes(p, modTime, modTime)
+}
+
+func writeFile(wc io.WriteCloser, r io.Reader) (err error) {
+	defer try.Close(&err, wc)
+
+	_, err = io.Copy(wc, r)
+	return
+}
+
+func (e *extractor) symlink(name, target string) error {
+	if target == "" {
+		return fmt.Errorf("empty symlink target: %s", name)
+	}
+
+	slashed := strings.ReplaceAll(target, `\`, "/")
+	if path.IsAbs(slashed) {
+		return fmt.Errorf("%w: absolute symlink target: %s -> %s", ErrUnsafePath, name, target)
+	}
+
+	resolved := path.Join(path.Dir(name), slashed)
+	if resolved == ".." || strings.HasPrefix(resolved, "../") {
+		return fmt.Errorf("%w: symlink target: %s -> %s", ErrUnsafePath, name, target)
+	}
+
+	p, err := e.path(name)
+	if err != nil {
+		return err
+	}
+
+	err = removeIfExists(p)
+	if err != nil {
+		return err
+	}
+
+	return os.Symlink(target, p)
+}
+
+func (e *extractor) hardlink(name, target string) error {
+	target, err := e.linkTarget(target)
+	if err != nil {
+		return err
+	}
+
+	src, err := e.path(target)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Lstat(src)
+	if err != nil {
+		return fmt.Errorf("hardlink target must be extracted before link: %s -> %s: %w", name, target, err)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("hardlink target is not a regular file: %s -> %s", name, target)
+	}
+
+	p, err := e.path(name)
+	if err != nil {
+		return err
+	}
+
+	err = removeIfExists(p)
+	if err != nil {
+		return err
+	}
+
+	return os.Link(src, p)
+}
+
+// linkTarget sanitizes and strips a hardlink target, which is the name of
+// another entry in the archive. Include patterns are deliberately ignored.
+func (e *extractor) linkTarget(target string) (string, error) {
+	target, err := cleanName(target)
+	if err != nil {
+		return "", err
+	}
+
+	parts := strings.Split(target, "/")
+	if target == "" || len(parts) <= e.opts.StripComponents {
+		return "", fmt.Errorf("hardlink target was stripped: %s", target)
+	}
+
+	return path.Join(parts[e.opts.StripComponents:]...), nil
+}
+
+func removeIfExists(p string) error {
+	err := os.Remove(p)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// finish applies directory permissions and modification times, deepest
+// first so updating a child does not change the modification time of
+// an already updated parent.
+func (e *extractor) finish() error {
+	slices.SortStableFunc(e.dirs, func(a, b dirEntry) int {
+		return len(b.path) - len(a.path)
+	})
+
+	for _, d := range e.dirs {
+		err := os.Chmod(d.path, d.mode)
+		if err != nil {
+			return err
+		}
+
+		err = os.Chtimes(d.path, d.modTime, d.modTime)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,324 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// entry describes a single, possibly hostile, archive entry. Archives are
+// built by hand since common tools will not create entries which escape
+// the archive root.
+type entry struct {
+	name     string
+	mode     fs.FileMode
+	body     string
+	link     string
+	hardlink bool
+}
+
+func tarBytes(t testing.TB, entries []entry) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		h := &tar.Header{
+			Name:     e.name,
+			Mode:     int64(e.mode.Perm()),
+			Typeflag: tar.TypeReg,
+			Size:     int64(len(e.body)),
+		}
+
+		switch {
+		case e.mode.IsDir():
+			h.Typeflag = tar.TypeDir
+			h.Size = 0
+		case e.hardlink:
+			h.Typeflag = tar.TypeLink
+			h.Linkname = e.link
+			h.Size = 0
+		case e.mode&fs.ModeSymlink != 0:
+			h.Typeflag = tar.TypeSymlink
+			h.Linkname = e.link
+			h.Size = 0
+		}
+
+		err := tw.WriteHeader(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = tw.Write([]byte(e.body[:h.Size]))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := tw.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func zipBytes(t testing.TB, entries []entry) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		h := &zip.FileHeader{
+			Name: e.name,
+		}
+		h.SetMode(e.mode)
+
+		w, err := zw.CreateHeader(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body := e.body
+		if e.mode&fs.ModeSymlink != 0 {
+			body = e.link
+		}
+
+		_, err = w.Write([]byte(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := zw.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// hostileArchives each contain an entry which would be written, or point,
+// outside the output directory.
+var hostileArchives = []struct {
+	name    string
+	entries []entry
+	zip     bool
+}{
+	{
+		name:    "parent traversal",
+		entries: []entry{{name: "../evil", mode: 0o644, body: "evil"}},
+		zip:     true,
+	},
+	{
+		name:    "nested traversal",
+		entries: []entry{{name: "a/b/../../../evil", mode: 0o644, body: "evil"}},
+		zip:     true,
+	},
+	{
+		name:    "absolute path",
+		entries: []entry{{name: "/tmp/evil", mode: 0o644, body: "evil"}},
+		zip:     true,
+	},
+	{
+		name:    "windows traversal",
+		entries: []entry{{name: `..\evil`, mode: 0o644, body: "evil"}},
+		zip:     true,
+	},
+	{
+		name:    "windows volume",
+		entries: []entry{{name: `C:\evil`, mode: 0o644, body: "evil"}},
+		zip:     true,
+	},
+	{
+		name:    "absolute symlink",
+		entries: []entry{{name: "etc", mode: fs.ModeSymlink | 0o777, link: "/etc"}},
+		zip:     true,
+	},
+	{
+		name:    "escaping symlink",
+		entries: []entry{{name: "a/up", mode: fs.ModeSymlink | 0o777, link: "../.."}},
+		zip:     true,
+	},
+	{
+		name: "write through symlink",
+		entries: []entry{
+			{name: "here", mode: fs.ModeSymlink | 0o777, link: "."},
+			{name: "here/evil", mode: 0o644, body: "evil"},
+		},
+		zip: true,
+	},
+	{
+		name: "chained symlinks",
+		entries: []entry{
+			{name: "a", mode: fs.ModeSymlink | 0o777, link: "."},
+			{name: "a/b", mode: fs.ModeSymlink | 0o777, link: ".."},
+		},
+		zip: true,
+	},
+	{
+		name: "hardlink traversal",
+		entries: []entry{
+			{name: "link", mode: 0o644, link: "../evil", hardlink: true},
+		},
+	},
+}
+
+// extractDirs returns an output directory nested in an otherwise empty
+// directory, so anything written outside of it can be detected.
+func extractDirs(t testing.TB) (root string, out string) {
+	root = t.TempDir()
+	return root, filepath.Join(root, "out")
+}
+
+// assertContained fails if anything other than the output directory was
+// written to root, or if any symlink within out resolves outside of it.
+func assertContained(t testing.TB, root, out string) {
+	t.Helper()
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if e.Name() != filepath.Base(out) {
+			t.Errorf("unexpected file written outside of output directory: %s", e.Name())
+		}
+	}
+
+	err = filepath.WalkDir(out, func(p string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && p == out {
+			return filepath.SkipAll
+		}
+		if err != nil {
+			return err
+		}
+		if d.Type()&fs.ModeSymlink == 0 {
+			return nil
+		}
+
+		target, err := os.Readlink(p)
+		if err != nil {
+			return err
+		}
+		if filepath.IsAbs(target) {
+			t.Errorf("absolute symlink within output directory: %s -> %s", p, target)
+			return nil
+		}
+
+		rel, err := filepath.Rel(out, filepath.Join(filepath.Dir(p), target))
+		if err != nil {
+			return err
+		}
+		if rel == ".." || strings.HasPrefix(rel, "../") {
+			t.Errorf("symlink escapes output directory: %s -> %s", p, target)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeArchive(t testing.TB, b []byte) string {
+	t.Helper()
+
+	filename := filepath.Join(t.TempDir(), "archive")
+	err := os.WriteFile(filename, b, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestExtractTarHostile(t *testing.T) {
+	for _, tc := range hostileArchives {
+		t.Run(tc.name, func(t *testing.T) {
+			filename := writeArchive(t, tarBytes(t, tc.entries))
+			root, out := extractDirs(t)
+
+			err := ExtractTar(t.Context(), filename, out, CompressionNone, ExtractOptions{})
+			if !errors.Is(err, ErrUnsafePath) {
+				t.Errorf("expected %v but received: %v", ErrUnsafePath, err)
+			}
+
+			assertContained(t, root, out)
+		})
+	}
+}
+
+func TestExtractZipHostile(t *testing.T) {
+	for _, tc := range hostileArchives {
+		if !tc.zip {
+			continue
+		}
+
+		t.Run(tc.name, func(t *testing.T) {
+			filename := writeArchive(t, zipBytes(t, tc.entries))
+			root, out := extractDirs(t)
+
+			err := ExtractZip(t.Context(), filename, out, ExtractOptions{})
+			if !errors.Is(err, ErrUnsafePath) {
+				t.Errorf("expected %v but received: %v", ErrUnsafePath, err)
+			}
+
+			assertContained(t, root, out)
+		})
+	}
+}
+
+func FuzzExtractTar(f *testing.F) {
+	for _, tc := range hostileArchives {
+		f.Add(tarBytes(f, tc.entries))
+	}
+	f.Add(tarBytes(f, []entry{
+		{name: "dir/", mode: fs.ModeDir | 0o755},
+		{name: "dir/file", mode: 0o644, body: "hello"},
+		{name: "dir/link", mode: fs.ModeSymlink | 0o777, link: "file"},
+		{name: "dir/hard", mode: 0o644, link: "dir/file", hardlink: true},
+	}))
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		filename := writeArchive(t, b)
+		root, out := extractDirs(t)
+
+		// Most inputs are not valid archives, only the output matters.
+		_ = ExtractTar(t.Context(), filename, out, CompressionNone, ExtractOptions{})
+
+		assertContained(t, root, out)
+	})
+}
+
+func FuzzExtractZip(f *testing.F) {
+	for _, tc := range hostileArchives {
+		if tc.zip {
+			f.Add(zipBytes(f, tc.entries))
+		}
+	}
+	f.Add(zipBytes(f, []entry{
+		{name: "dir/", mode: fs.ModeDir | 0o755},
+		{name: "dir/file", mode: 0o644, body: "hello"},
+		{name: "dir/link", mode: fs.ModeSymlink | 0o777, link: "file"},
+	}))
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		filename := writeArchive(t, b)
+		root, out := extractDirs(t)
+
+		// Most inputs are not valid archives, only the output matters.
+		_ = ExtractZip(t.Context(), filename, out, ExtractOptions{})
+
+		assertContained(t, root, out)
+	})
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"io"
+	"io/fs"
+	"os"
+)
+
+const (
+	EntryFile      = "file"
+	EntryDirectory = "directory"
+	EntrySymlink   = "symlink"
+	EntryHardlink  = "hardlink"
+	EntryOther     = "other"
+)
+
+// Entry describes a single archive entry without its contents.
+type Entry struct {
+	Name string `json:"name"`
+
+	// Uncompressed size in bytes.
+	Size int64 `json:"size"`
+
+	// Permission bits.
+	Mode uint32 `json:"mode"`
+
+	// Modification time in seconds since the Unix epoch.
+	ModTime int64 `json:"modTime"`
+
+	Type string `json:"type"`
+
+	// Target of symlinks and hardlinks.
+	Link string `json:"link,omitempty"`
+}
+
+func ListTar(filename, compression string) (entries []Entry, err error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	stream, err := decompress(bufio.NewReader(f), compression, filename)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	tr := tar.NewReader(stream)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		e := Entry{
+			Name:    h.Name,
+			Size:    h.Size,
+			Mode:    uint32(h.FileInfo().Mode().Perm()),
+			ModTime: h.ModTime.Unix(),
+			Type:    EntryOther,
+			Link:    h.Linkname,
+		}
+
+		switch h.Typeflag {
+		case tar.TypeReg, tar.TypeRegA:
+			e.Type = EntryFile
+		case tar.TypeDir:
+			e.Type = EntryDirectory
+		case tar.TypeSymlink:
+			e.Type = EntrySymlink
+		case tar.TypeLink:
+			e.Type = EntryHardlink
+		}
+
+		entries = append(entries, e)
+	}
+}
+
+func ListZip(filename string) ([]Entry, error) {
+	zr, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	entries := make([]Entry, 0, len(zr.File))
+	for _, zipFile := range zr.File {
+		mode := zipFile.Mode()
+
+		e := Entry{
+			Name:    zipFile.Name,
+			Size:    int64(zipFile.UncompressedSize64),
+			Mode:    uint32(mode.Perm()),
+			ModTime: zipFile.Modified.Unix(),
+			Type:    EntryOther,
+		}
+
+		switch {
+		case mode.IsRegular():
+			e.Type = EntryFile
+		case mode.IsDir():
+			e.Type = EntryDirectory
+		case mode&fs.ModeSymlink != 0:
+			e.Type = EntrySymlink
+
+			target, err := readZipSymlink(zipFile)
+			if err != nil {
+				return nil, err
+			}
+			e.Link = target
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
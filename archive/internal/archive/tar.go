@@ -7,25 +7,25 @@ package archive
 
 import (
 	"archive/tar"
+	"bufio"
 	"compress/gzip"
 	"context"
+	"fmt"
 	"io"
+	"io/fs"
 	"log/slog"
 	"os"
-	"path"
-
-	"dagger/archive/internal/dagger"
+	"path/filepath"
+	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/z5labs/sdk-go/try"
 )
 
-func ExtractTar(ctx context.Context, filename, out string, isGziped bool) error {
-	_, span := dagger.Tracer().Start(ctx, "archive.ExtractTar")
-	defer span.End()
-
+func ExtractTar(ctx context.Context, filename, out, compression string, opts ExtractOptions) error {
 	log := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{}))
 
-	err := os.MkdirAll(out, os.ModeDir)
+	e, err := newExtractor(out, opts)
 	if err != nil {
 		log.ErrorContext(ctx, "failed to create output directory", slog.Any("error", err))
 		return err
@@ -38,55 +38,155 @@ func ExtractTar(ctx context.Context, filename, out string, isGziped bool) error
 	}
 	defer f.Close()
 
-	var stream io.Reader = f
-	if isGziped {
-		stream, err = gzip.NewReader(stream)
-		if err != nil {
-			log.ErrorContext(ctx, "failed to create gzip reader", slog.Any("error", err))
-			return err
-		}
+	stream, err := decompress(bufio.NewReader(f), compression, filename)
+	if err != nil {
+		log.ErrorContext(ctx, "failed to create decompressor", slog.String("compression", compression), slog.Any("error", err))
+		return err
 	}
+	defer stream.Close()
 
 	tr := tar.NewReader(stream)
 	for {
 		h, err := tr.Next()
 		if err == io.EOF {
-			return nil
+			return e.finish()
 		}
 		if err != nil {
 			log.ErrorContext(ctx, "failed to get header", slog.Any("error", err))
 			return err
 		}
 
-		if h.FileInfo().IsDir() {
+		name, err := e.name(h.Name)
+		if err != nil {
+			log.ErrorContext(ctx, "unsafe entry name", slog.String("name", h.Name), slog.Any("error", err))
+			return err
+		}
+		if name == "" {
 			continue
 		}
 
-		dir, _ := path.Split(h.Name)
-		if dir != "" {
-			err = os.MkdirAll(path.Join(out, dir), os.ModeDir)
+		switch h.Typeflag {
+		case tar.TypeDir:
+			err = e.dir(name, h.FileInfo().Mode(), h.ModTime)
+		case tar.TypeReg, tar.TypeRegA:
+			err = e.file(name, h.FileInfo().Mode(), h.ModTime, tr)
+		case tar.TypeSymlink:
+			err = e.symlink(name, h.Linkname)
+		case tar.TypeLink:
+			err = e.hardlink(name, h.Linkname)
+		default:
+			log.WarnContext(ctx, "skipping unsupported tar entry", slog.String("name", h.Name), slog.Any("type", h.Typeflag))
+			continue
+		}
+		if err != nil {
+			log.ErrorContext(ctx, "failed to extract tar entry", slog.String("name", h.Name), slog.Any("error", err))
+			return err
+		}
+	}
+}
+
+func CreateTar(ctx context.Context, dir, filename string, opts CreateOptions) (err error) {
+	log := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{}))
+
+	f, err := os.Create(filename)
+	if err != nil {
+		log.ErrorContext(ctx, "failed to create file", slog.Any("error", err))
+		return err
+	}
+	defer try.Close(&err, f)
+
+	var stream io.Writer = f
+	switch opts.Compression {
+	case CompressionNone:
+	case CompressionGzip:
+		gw := gzip.NewWriter(f)
+		defer try.Close(&err, gw)
+
+		stream = gw
+	case CompressionZstd:
+		var zw *zstd.Encoder
+		zw, err = zstd.NewWriter(f)
+		if err != nil {
+			log.ErrorContext(ctx, "failed to create zstd writer", slog.Any("error", err))
+			return err
+		}
+		defer try.Close(&err, zw)
+
+		stream = zw
+	default:
+		return fmt.Errorf("unsupported tar compression: %s", opts.Compression)
+	}
+
+	tw := tar.NewWriter(stream)
+	defer try.Close(&err, tw)
+
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if name == "." {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		var link string
+		if info.Mode()&fs.ModeSymlink != 0 {
+			link, err = os.Readlink(p)
 			if err != nil {
-				log.ErrorContext(ctx, "failed to create output sub dir", slog.Any("error", err))
 				return err
 			}
 		}
 
-		f, err := os.Create(path.Join(out, h.Name))
+		h, err := tar.FileInfoHeader(info, link)
 		if err != nil {
 			return err
 		}
 
-		err = copyTarFile(f, tr)
+		h.Name = filepath.ToSlash(name)
+		if d.IsDir() {
+			h.Name += "/"
+		}
+
+		if opts.Reproducible {
+			h.Uid = 0
+			h.Gid = 0
+			h.Uname = ""
+			h.Gname = ""
+			h.ModTime = opts.ModTime
+			h.AccessTime = time.Time{}
+			h.ChangeTime = time.Time{}
+		}
+
+		err = tw.WriteHeader(h)
 		if err != nil {
-			log.ErrorContext(ctx, "failed to write tar content to output directory", slog.Any("error", err))
+			log.ErrorContext(ctx, "failed to write header", slog.Any("error", err))
 			return err
 		}
-	}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		return copyFileTo(tw, p)
+	})
 }
 
-func copyTarFile(wc io.WriteCloser, r io.Reader) (err error) {
-	defer try.Close(&err, wc)
+func copyFileTo(w io.Writer, filename string) (err error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer try.Close(&err, f)
 
-	_, err = io.Copy(wc, r)
+	_, err = io.Copy(w, f)
 	return
 }
@@ -8,23 +8,24 @@ package archive
 import (
 	"archive/zip"
 	"context"
+	"fmt"
 	"io"
+	"io/fs"
 	"log/slog"
 	"os"
-	"path"
-
-	"dagger/archive/internal/dagger"
+	"path/filepath"
 
 	"github.com/z5labs/sdk-go/try"
 )
 
-func ExtractZip(ctx context.Context, filename, out string) error {
-	_, span := dagger.Tracer().Start(ctx, "archive.ExtractZip")
-	defer span.End()
+// maxSymlinkTarget bounds how much of a zip symlink entry is read
+// since its contents are the link target and should never be large.
+const maxSymlinkTarget = 4096
 
+func ExtractZip(ctx context.Context, filename, out string, opts ExtractOptions) error {
 	log := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{}))
 
-	err := os.MkdirAll(out, os.ModeDir)
+	e, err := newExtractor(out, opts)
 	if err != nil {
 		log.ErrorContext(ctx, "failed to create output directory", slog.Any("error", err))
 		return err
@@ -38,48 +39,139 @@ func ExtractZip(ctx context.Context, filename, out string) error {
 	defer zr.Close()
 
 	for _, zipFile := range zr.File {
-		if !zipFile.FileInfo().IsDir() {
+		name, err := e.name(zipFile.Name)
+		if err != nil {
+			log.ErrorContext(ctx, "unsafe entry name", slog.String("name", zipFile.Name), slog.Any("error", err))
+			return err
+		}
+		if name == "" {
 			continue
 		}
 
-		err = os.MkdirAll(path.Join(out, zipFile.Name), os.ModeDir)
+		err = extractZipFile(e, name, zipFile)
 		if err != nil {
-			log.ErrorContext(ctx, "failed to create output dir", slog.Any("error", err))
+			log.ErrorContext(ctx, "failed to extract zip entry", slog.String("name", zipFile.Name), slog.Any("error", err))
 			return err
 		}
 	}
 
-	for _, zipFile := range zr.File {
-		if zipFile.FileInfo().IsDir() {
-			continue
+	return e.finish()
+}
+
+func extractZipFile(e *extractor, name string, zipFile *zip.File) (err error) {
+	mode := zipFile.Mode()
+	if mode.IsDir() {
+		return e.dir(name, mode, zipFile.Modified)
+	}
+
+	if mode&fs.ModeSymlink != 0 {
+		target, err := readZipSymlink(zipFile)
+		if err != nil {
+			return err
 		}
 
-		out, err := os.Create(path.Join(out, zipFile.Name))
+		return e.symlink(name, target)
+	}
+
+	rc, err := zipFile.Open()
+	if err != nil {
+		return err
+	}
+	defer try.Close(&err, rc)
+
+	return e.file(name, mode, zipFile.Modified, rc)
+}
+
+// readZipSymlink reads the target of a symlink entry, which zip stores
+// as the entry contents.
+func readZipSymlink(zipFile *zip.File) (target string, err error) {
+	rc, err := zipFile.Open()
+	if err != nil {
+		return "", err
+	}
+	defer try.Close(&err, rc)
+
+	b, err := io.ReadAll(io.LimitReader(rc, maxSymlinkTarget+1))
+	if err != nil {
+		return "", err
+	}
+	if len(b) > maxSymlinkTarget {
+		return "", fmt.Errorf("symlink target too long: %s", zipFile.Name)
+	}
+
+	return string(b), nil
+}
+
+func CreateZip(ctx context.Context, dir, filename string, opts CreateOptions) (err error) {
+	log := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{}))
+
+	if opts.Compression != CompressionNone {
+		return fmt.Errorf("unsupported zip compression: %s", opts.Compression)
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		log.ErrorContext(ctx, "failed to create file", slog.Any("error", err))
+		return err
+	}
+	defer try.Close(&err, f)
+
+	zw := zip.NewWriter(f)
+	defer try.Close(&err, zw)
+
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
 		if err != nil {
-			log.ErrorContext(ctx, "failed to create output file", slog.Any("error", err))
 			return err
 		}
 
-		rc, err := zipFile.Open()
+		name, err := filepath.Rel(dir, p)
 		if err != nil {
-			log.ErrorContext(ctx, "failed to open zip content", slog.Any("error", err))
 			return err
 		}
+		if name == "." {
+			return nil
+		}
 
-		err = copyZipFile(out, rc)
+		info, err := d.Info()
 		if err != nil {
-			log.ErrorContext(ctx, "failed to write zip content to output directory", slog.Any("error", err))
 			return err
 		}
-	}
 
-	return nil
-}
+		h, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
 
-func copyZipFile(wc io.WriteCloser, rc io.ReadCloser) (err error) {
-	defer try.Close(&err, wc)
-	defer try.Close(&err, rc)
+		h.Name = filepath.ToSlash(name)
+		if d.IsDir() {
+			h.Name += "/"
+		} else {
+			h.Method = zip.Deflate
+		}
+
+		if opts.Reproducible {
+			h.Modified = opts.ModTime
+		}
 
-	_, err = io.Copy(wc, rc)
-	return
+		w, err := zw.CreateHeader(h)
+		if err != nil {
+			log.ErrorContext(ctx, "failed to write header", slog.Any("error", err))
+			return err
+		}
+
+		switch {
+		case info.Mode().IsRegular():
+			return copyFileTo(w, p)
+		case info.Mode()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+
+			_, err = io.WriteString(w, link)
+			return err
+		default:
+			return nil
+		}
+	})
 }
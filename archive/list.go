@@ -0,0 +1,79 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+
+	"dagger/archive/internal/dagger"
+)
+
+// Entry
+type Entry struct {
+	// Path of the entry within the archive.
+	Name string
+
+	// Uncompressed size in bytes.
+	Size int
+
+	// Permission bits.
+	Mode int
+
+	// Modification time in seconds since the Unix epoch.
+	ModTime int
+
+	// One of file, directory, symlink, hardlink or other.
+	Type string
+
+	// Target of symlinks and hardlinks.
+	Link string
+}
+
+func list(ctx context.Context, file *dagger.File, format string, compression Compression) ([]*Entry, error) {
+	ctr, filename, err := mountArchive(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := []string{"archive", "list", "-format", format}
+	if compression != "" {
+		cmd = append(cmd, "-compression", string(compression))
+	}
+	cmd = append(cmd, filename)
+
+	stdout, err := ctr.WithExec(cmd).Stdout(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []struct {
+		Name    string `json:"name"`
+		Size    int    `json:"size"`
+		Mode    int    `json:"mode"`
+		ModTime int    `json:"modTime"`
+		Type    string `json:"type"`
+		Link    string `json:"link"`
+	}
+	err = json.Unmarshal([]byte(stdout), &entries)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*Entry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, &Entry{
+			Name:    e.Name,
+			Size:    e.Size,
+			Mode:    e.Mode,
+			ModTime: e.ModTime,
+			Type:    e.Type,
+			Link:    e.Link,
+		})
+	}
+
+	return out, nil
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+
+	"dagger/archive/internal/dagger"
 )
 
 type Archive struct{}
@@ -9,3 +11,40 @@ type Archive struct{}
 func New(ctx context.Context) *Archive {
 	return &Archive{}
 }
+
+// Extract a tar or zip archive, detecting the format and any compression
+// from its contents.
+func (m *Archive) Extract(
+	ctx context.Context,
+
+	file *dagger.File,
+
+	// Number of leading path elements to remove from entry names.
+	// +optional
+	stripComponents int,
+
+	// Only extract entries matching these globs, after stripping.
+	// +optional
+	include []string,
+) (*dagger.Directory, error) {
+	return extract(ctx, file, extractArgs{
+		format:          "auto",
+		stripComponents: stripComponents,
+		include:         include,
+	})
+}
+
+// Extract a single file from a tar or zip archive, detecting the format and
+// any compression from its contents.
+func (m *Archive) File(
+	ctx context.Context,
+
+	file *dagger.File,
+
+	// Path of the file within the archive.
+	path string,
+) (*dagger.File, error) {
+	return extractFile(ctx, file, extractArgs{
+		format: "auto",
+	}, path)
+}
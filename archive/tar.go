@@ -8,7 +8,6 @@ package main
 import (
 	"context"
 
-	"dagger/archive/internal/archive"
 	"dagger/archive/internal/dagger"
 )
 
@@ -25,23 +24,113 @@ func (t *Tar) Extract(
 	file *dagger.File,
 
 	// Enable gzip decompression.
+	// Deprecated: use compression instead.
 	// +optional
 	gzip bool,
+
+	// Compression of the archive, detected from its contents or name by default.
+	// +default="auto"
+	compression Compression,
+
+	// Number of leading path elements to remove from entry names.
+	// +optional
+	stripComponents int,
+
+	// Only extract entries matching these globs, after stripping.
+	// +optional
+	include []string,
 ) (*dagger.Directory, error) {
-	name, err := file.Name(ctx)
-	if err != nil {
-		return nil, err
+	if gzip {
+		compression = Gzip
 	}
 
-	_, err = file.Export(ctx, name)
-	if err != nil {
-		return nil, err
-	}
+	return extract(ctx, file, extractArgs{
+		format:          "tar",
+		compression:     compression,
+		stripComponents: stripComponents,
+		include:         include,
+	})
+}
 
-	err = archive.ExtractTar(ctx, name, "out", gzip)
-	if err != nil {
-		return nil, err
-	}
+// List the entries of a tar archive without extracting it.
+func (t *Tar) List(
+	ctx context.Context,
+
+	file *dagger.File,
+
+	// Compression of the archive, detected from its contents or name by default.
+	// +default="auto"
+	compression Compression,
+) ([]*Entry, error) {
+	return list(ctx, file, "tar", compression)
+}
+
+// Extract a single file from a tar archive.
+func (t *Tar) File(
+	ctx context.Context,
+
+	file *dagger.File,
+
+	// Path of the file within the archive.
+	path string,
+
+	// Compression of the archive, detected from its contents or name by default.
+	// +default="auto"
+	compression Compression,
+) (*dagger.File, error) {
+	return extractFile(ctx, file, extractArgs{
+		format:      "tar",
+		compression: compression,
+	}, path)
+}
+
+type Compression string
+
+const (
+	Auto  Compression = "auto"
+	None  Compression = "none"
+	Gzip  Compression = "gzip"
+	Zstd  Compression = "zstd"
+	Xz    Compression = "xz"
+	Bzip2 Compression = "bzip2"
+)
+
+// Create a tar archive from the contents of a directory.
+func (t *Tar) Create(
+	dir *dagger.Directory,
+
+	// Name of the created archive.
+	// +default="archive.tar"
+	name string,
+
+	// Only include paths matching these globs.
+	// +optional
+	include []string,
+
+	// Exclude paths matching these globs.
+	// +optional
+	exclude []string,
+
+	// Compression of the archive, only none, gzip and zstd are supported.
+	// +default="none"
+	compression Compression,
+
+	// Set the owner of every entry to root and the modification time to mtime.
+	// +optional
+	reproducible bool,
 
-	return dag.CurrentModule().Workdir("out"), nil
+	// Modification time of every entry, in seconds since the Unix epoch, when reproducible.
+	// Defaults to 1980-01-01, the same as zip archives.
+	// +default=315532800
+	mtime int,
+) (*dagger.File, error) {
+	return create(dir, createArgs{
+		format:       "tar",
+		name:         name,
+		include:      include,
+		exclude:      exclude,
+		compression:  compression,
+		reproducible: reproducible,
+		mtime:        mtime,
+	})
 }
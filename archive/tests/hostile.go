@@ -0,0 +1,265 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"dagger/archive-tests/internal/dagger"
+
+	"github.com/sourcegraph/conc/pool"
+)
+
+// entry describes a single, possibly hostile, archive entry. Archives
+// are built by hand since neither the archive module nor common tools
+// will create entries which escape the archive root.
+type entry struct {
+	Name string
+	Mode fs.FileMode
+	Body string
+	Link string
+
+	// Only used by tar to distinguish hardlinks from symlinks.
+	Hardlink bool
+}
+
+func tarFile(name string, entries ...entry) (*dagger.File, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		h := &tar.Header{
+			Name:     e.Name,
+			Mode:     int64(e.Mode.Perm()),
+			Typeflag: tar.TypeReg,
+			Size:     int64(len(e.Body)),
+		}
+
+		switch {
+		case e.Mode.IsDir():
+			h.Typeflag = tar.TypeDir
+			h.Size = 0
+		case e.Hardlink:
+			h.Typeflag = tar.TypeLink
+			h.Linkname = e.Link
+			h.Size = 0
+		case e.Mode&fs.ModeSymlink != 0:
+			h.Typeflag = tar.TypeSymlink
+			h.Linkname = e.Link
+			h.Size = 0
+		}
+
+		err := tw.WriteHeader(h)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tw.Write([]byte(e.Body[:h.Size]))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := tw.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return workdirFile(name, buf.Bytes())
+}
+
+func zipFile(name string, entries ...entry) (*dagger.File, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		h := &zip.FileHeader{
+			Name: e.Name,
+		}
+		h.SetMode(e.Mode)
+
+		w, err := zw.CreateHeader(h)
+		if err != nil {
+			return nil, err
+		}
+
+		body := e.Body
+		if e.Mode&fs.ModeSymlink != 0 {
+			body = e.Link
+		}
+
+		_, err = w.Write([]byte(body))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := zw.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return workdirFile(name, buf.Bytes())
+}
+
+func workdirFile(name string, b []byte) (*dagger.File, error) {
+	err := os.WriteFile(name, b, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return dag.CurrentModule().WorkdirFile(name), nil
+}
+
+// hostileArchives are expected to fail extraction since each contains
+// an entry which would be written, or point, outside the output directory.
+var hostileArchives = map[string][]entry{
+	"parent-traversal": {
+		{Name: "../evil", Mode: 0o644, Body: "evil"},
+	},
+	"nested-traversal": {
+		{Name: "a/b/../../../evil", Mode: 0o644, Body: "evil"},
+	},
+	"absolute-path": {
+		{Name: "/tmp/evil", Mode: 0o644, Body: "evil"},
+	},
+	"windows-traversal": {
+		{Name: `..\evil`, Mode: 0o644, Body: "evil"},
+	},
+	"absolute-symlink": {
+		{Name: "etc", Mode: fs.ModeSymlink | 0o777, Link: "/etc"},
+	},
+	"escaping-symlink": {
+		{Name: "a/up", Mode: fs.ModeSymlink | 0o777, Link: "../.."},
+	},
+	"write-through-symlink": {
+		{Name: "here", Mode: fs.ModeSymlink | 0o777, Link: "."},
+		{Name: "here/evil", Mode: 0o644, Body: "evil"},
+	},
+	"chained-symlinks": {
+		{Name: "a", Mode: fs.ModeSymlink | 0o777, Link: "."},
+		{Name: "a/b", Mode: fs.ModeSymlink | 0o777, Link: ".."},
+	},
+}
+
+// extractFails runs each extraction concurrently and reports the cases
+// which unexpectedly succeeded or failed for any other reason than an
+// entry escaping the output directory.
+func extractFails(ctx context.Context, extract func(name string, entries []entry) (*dagger.Directory, error)) error {
+	ep := pool.New().WithErrors().WithContext(ctx)
+
+	for name, entries := range hostileArchives {
+		ep.Go(func(ctx context.Context) error {
+			dir, err := extract(name, entries)
+			if err != nil {
+				return err
+			}
+
+			_, err = dir.Sync(ctx)
+			if err == nil {
+				return fmt.Errorf("%s: expected extraction to fail", name)
+			}
+			if !strings.Contains(err.Error(), "escapes output directory") {
+				return fmt.Errorf("%s: expected extraction to fail due to an unsafe path: %w", name, err)
+			}
+
+			return nil
+		})
+	}
+
+	return ep.Wait()
+}
+
+// validArchive contains every supported entry type along with a
+// leading directory to be stripped.
+var validArchive = []entry{
+	{Name: "release/", Mode: fs.ModeDir | 0o755},
+	{Name: "release/bin/", Mode: fs.ModeDir | 0o755},
+	{Name: "release/bin/tool", Mode: 0o755, Body: "#!/bin/sh"},
+	{Name: "release/empty/", Mode: fs.ModeDir | 0o700},
+	{Name: "release/README.md", Mode: 0o644, Body: "# readme"},
+	{Name: "release/tool", Mode: fs.ModeSymlink | 0o777, Link: "bin/tool"},
+}
+
+// checkExtracted asserts the layout of validArchive after extraction with
+// a single leading path element stripped.
+func checkExtracted(ctx context.Context, dir *dagger.Directory) error {
+	_, err := dag.Container().
+		From("alpine").
+		WithMountedDirectory("/out", dir).
+		WithExec([]string{"sh", "-c", `
+			set -e
+			test -x /out/bin/tool
+			test -d /out/empty
+			test "$(stat -c %a /out/empty)" = 700
+			test "$(stat -c %a /out/README.md)" = 644
+			test "$(readlink /out/tool)" = bin/tool
+		`}).
+		Sync(ctx)
+	return err
+}
+
+// checkListed asserts the entries listed for validArchive.
+func checkListed(ctx context.Context, entries []dagger.ArchiveEntry) error {
+	if len(entries) != len(validArchive) {
+		return fmt.Errorf("expected %d entries instead of: %d", len(validArchive), len(entries))
+	}
+
+	for i, e := range entries {
+		name, err := e.Name(ctx)
+		if err != nil {
+			return err
+		}
+
+		if name != validArchive[i].Name {
+			return fmt.Errorf("expected entry %d to be %s instead of: %s", i, validArchive[i].Name, name)
+		}
+	}
+
+	tool := entries[2]
+
+	typ, err := tool.Type(ctx)
+	if err != nil {
+		return err
+	}
+
+	mode, err := tool.Mode(ctx)
+	if err != nil {
+		return err
+	}
+
+	size, err := tool.Size(ctx)
+	if err != nil {
+		return err
+	}
+
+	if typ != "file" || mode != 0o755 || size != len("#!/bin/sh") {
+		return fmt.Errorf("unexpected entry metadata for %s: type=%s mode=%o size=%d", validArchive[2].Name, typ, mode, size)
+	}
+
+	link := entries[5]
+
+	typ, err = link.Type(ctx)
+	if err != nil {
+		return err
+	}
+
+	target, err := link.Link(ctx)
+	if err != nil {
+		return err
+	}
+
+	if typ != "symlink" || target != "bin/tool" {
+		return fmt.Errorf("unexpected entry metadata for %s: type=%s link=%s", validArchive[5].Name, typ, target)
+	}
+
+	return nil
+}
@@ -7,6 +7,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 
 	"dagger/archive-tests/internal/dagger"
 
@@ -29,6 +30,112 @@ func (m *ArchiveTests) All(ctx context.Context) error {
 
 	ep.Go(m.Zip().All)
 	ep.Go(m.Tar().All)
+	ep.Go(m.ExtractTest)
+	ep.Go(m.ExtractConcurrentTest)
+	ep.Go(m.CreateReproducibleModTimeTest)
 
 	return ep.Wait()
 }
+
+func (m *ArchiveTests) ExtractTest(ctx context.Context) error {
+	dir := dag.Directory().WithNewFile("hello.txt", "hello world")
+
+	testCases := map[string]*dagger.File{
+		"zip": m.Archive.Zip().Create(dir),
+		"tar": m.Archive.Tar().Create(dir),
+		"tar.gz": m.Archive.Tar().Create(dir, dagger.ArchiveTarCreateOpts{
+			Name:        "archive.tar.gz",
+			Compression: dagger.ArchiveCompressionGzip,
+		}),
+		"tar.zst": m.Archive.Tar().Create(dir, dagger.ArchiveTarCreateOpts{
+			Name:        "archive.tar.zst",
+			Compression: dagger.ArchiveCompressionZstd,
+		}),
+	}
+
+	ep := pool.New().WithErrors().WithContext(ctx)
+
+	for name, f := range testCases {
+		ep.Go(func(ctx context.Context) error {
+			for _, extracted := range []*dagger.File{
+				m.Archive.Extract(f).File("hello.txt"),
+				m.Archive.File(f, "hello.txt"),
+			} {
+				contents, err := extracted.Contents(ctx)
+				if err != nil {
+					return fmt.Errorf("%s: %w", name, err)
+				}
+
+				if contents != "hello world" {
+					return fmt.Errorf("%s: unexpected file contents: %s", name, contents)
+				}
+			}
+
+			return nil
+		})
+	}
+
+	return ep.Wait()
+}
+
+// Extracting different archives with the same name at the same time must
+// not mix up their contents.
+func (m *ArchiveTests) ExtractConcurrentTest(ctx context.Context) error {
+	ep := pool.New().WithErrors().WithContext(ctx)
+
+	for i := range 8 {
+		ep.Go(func(ctx context.Context) error {
+			expected := fmt.Sprintf("archive %d", i)
+
+			f := m.Archive.Tar().Create(dag.Directory().WithNewFile("hello.txt", expected))
+
+			contents, err := m.Archive.Extract(f).File("hello.txt").Contents(ctx)
+			if err != nil {
+				return err
+			}
+
+			if contents != expected {
+				return fmt.Errorf("expected %q instead of: %q", expected, contents)
+			}
+
+			return nil
+		})
+	}
+
+	return ep.Wait()
+}
+
+// Reproducible tar and zip archives of the same contents must default to
+// the same modification time.
+func (m *ArchiveTests) CreateReproducibleModTimeTest(ctx context.Context) error {
+	dir := dag.Directory().WithNewFile("hello.txt", "hello world")
+
+	tarEntries, err := m.Archive.Tar().List(ctx, m.Archive.Tar().Create(dir, dagger.ArchiveTarCreateOpts{
+		Reproducible: true,
+	}))
+	if err != nil {
+		return err
+	}
+
+	zipEntries, err := m.Archive.Zip().List(ctx, m.Archive.Zip().Create(dir, dagger.ArchiveZipCreateOpts{
+		Reproducible: true,
+	}))
+	if err != nil {
+		return err
+	}
+
+	for _, entries := range [][]dagger.ArchiveEntry{tarEntries, zipEntries} {
+		for _, e := range entries {
+			modTime, err := e.ModTime(ctx)
+			if err != nil {
+				return err
+			}
+
+			if modTime != 315532800 {
+				return fmt.Errorf("expected entries to default to 1980-01-01 instead of: %d", modTime)
+			}
+		}
+	}
+
+	return nil
+}
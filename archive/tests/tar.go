@@ -7,8 +7,11 @@ package main
 
 import (
 	"context"
-	"dagger/archive-tests/internal/dagger"
+	"errors"
 	"fmt"
+	"strings"
+
+	"dagger/archive-tests/internal/dagger"
 
 	"github.com/sourcegraph/conc/pool"
 )
@@ -28,6 +31,19 @@ func (t *Tar) All(ctx context.Context) error {
 	ep := pool.New().WithErrors().WithContext(ctx)
 
 	ep.Go(t.FromUrlTest)
+	ep.Go(t.CreateTest)
+	ep.Go(t.CreateWithFiltersTest)
+	ep.Go(t.CreateZstdTest)
+	ep.Go(t.CreateReproducibleTest)
+	ep.Go(t.CreateInvalidNameTest)
+	ep.Go(t.ExtractUnsafeTest)
+	ep.Go(t.ExtractModesTest)
+	ep.Go(t.ExtractStripComponentsTest)
+	ep.Go(t.ExtractIncludeTest)
+	ep.Go(t.ListTest)
+	ep.Go(t.FileTest)
+	ep.Go(t.ExtractHardlinkTest)
+	ep.Go(t.ExtractCompressionTest)
 
 	return ep.Wait()
 }
@@ -50,3 +66,305 @@ func (t *Tar) FromUrlTest(ctx context.Context) error {
 
 	return nil
 }
+
+func (t *Tar) CreateTest(ctx context.Context) error {
+	dir := dag.Directory().
+		WithNewFile("hello.txt", "hello world").
+		WithNewFile("nested/bin", "#!/bin/sh", dagger.DirectoryWithNewFileOpts{
+			Permissions: 0o755,
+		})
+
+	f := t.Tar.Create(dir, dagger.ArchiveTarCreateOpts{
+		Compression: dagger.ArchiveCompressionGzip,
+	})
+
+	out := t.Tar.Extract(f)
+
+	contents, err := out.File("hello.txt").Contents(ctx)
+	if err != nil {
+		return err
+	}
+
+	if contents != "hello world" {
+		return errors.New("unexpected file contents after round trip: " + contents)
+	}
+
+	entries, err := out.Entries(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) != 2 {
+		return fmt.Errorf("expected 2 top level entries in directory instead of: %d", len(entries))
+	}
+
+	return nil
+}
+
+func (t *Tar) CreateWithFiltersTest(ctx context.Context) error {
+	dir := dag.Directory().
+		WithNewFile("main.go", "package main").
+		WithNewFile("main_test.go", "package main").
+		WithNewFile("README.md", "# readme")
+
+	f := t.Tar.Create(dir, dagger.ArchiveTarCreateOpts{
+		Include: []string{"*.go"},
+		Exclude: []string{"*_test.go"},
+	})
+
+	entries, err := t.Tar.Extract(f).Entries(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) != 1 || entries[0] != "main.go" {
+		return fmt.Errorf("expected only main.go to be archived: %v", entries)
+	}
+
+	return nil
+}
+
+func (t *Tar) CreateZstdTest(ctx context.Context) error {
+	dir := dag.Directory().WithNewFile("hello.txt", "hello world")
+
+	f := t.Tar.Create(dir, dagger.ArchiveTarCreateOpts{
+		Name:        "archive.tar.zst",
+		Compression: dagger.ArchiveCompressionZstd,
+	})
+
+	stdout, err := dag.Container().
+		From("alpine").
+		WithExec([]string{"apk", "add", "tar", "zstd"}).
+		WithMountedFile("/archive.tar.zst", f).
+		WithExec([]string{"tar", "--zstd", "-tf", "/archive.tar.zst"}).
+		Stdout(ctx)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(stdout) != "hello.txt" {
+		return errors.New("unexpected zstd archive contents: " + stdout)
+	}
+
+	return nil
+}
+
+func (t *Tar) CreateReproducibleTest(ctx context.Context) error {
+	dir := dag.Directory().WithNewFile("hello.txt", "hello world")
+
+	opts := dagger.ArchiveTarCreateOpts{
+		Compression:  dagger.ArchiveCompressionGzip,
+		Reproducible: true,
+	}
+
+	first, err := t.Tar.Create(dir.WithTimestamps(1000), opts).Digest(ctx, dagger.FileDigestOpts{
+		ExcludeMetadata: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	second, err := t.Tar.Create(dir.WithTimestamps(2000), opts).Digest(ctx, dagger.FileDigestOpts{
+		ExcludeMetadata: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	if first != second {
+		return fmt.Errorf("expected reproducible archives to be identical: %s != %s", first, second)
+	}
+
+	return nil
+}
+
+func (t *Tar) ExtractUnsafeTest(ctx context.Context) error {
+	return extractFails(ctx, func(name string, entries []entry) (*dagger.Directory, error) {
+		f, err := tarFile(name+".tar", entries...)
+		if err != nil {
+			return nil, err
+		}
+
+		return t.Tar.Extract(f), nil
+	})
+}
+
+func (t *Tar) ExtractModesTest(ctx context.Context) error {
+	f, err := tarFile("modes.tar", validArchive...)
+	if err != nil {
+		return err
+	}
+
+	out := t.Tar.Extract(f).Directory("release")
+
+	return checkExtracted(ctx, out)
+}
+
+func (t *Tar) ExtractStripComponentsTest(ctx context.Context) error {
+	f, err := tarFile("strip.tar", validArchive...)
+	if err != nil {
+		return err
+	}
+
+	out := t.Tar.Extract(f, dagger.ArchiveTarExtractOpts{
+		StripComponents: 1,
+	})
+
+	return checkExtracted(ctx, out)
+}
+
+func (t *Tar) ExtractIncludeTest(ctx context.Context) error {
+	f, err := tarFile("include.tar", validArchive...)
+	if err != nil {
+		return err
+	}
+
+	out := t.Tar.Extract(f, dagger.ArchiveTarExtractOpts{
+		StripComponents: 1,
+		Include:         []string{"bin"},
+	})
+
+	entries, err := out.Entries(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) != 1 {
+		return fmt.Errorf("expected only the bin directory to be extracted: %v", entries)
+	}
+
+	contents, err := out.File("bin/tool").Contents(ctx)
+	if err != nil {
+		return err
+	}
+
+	if contents != "#!/bin/sh" {
+		return errors.New("unexpected file contents: " + contents)
+	}
+
+	return nil
+}
+
+func (t *Tar) ExtractHardlinkTest(ctx context.Context) error {
+	f, err := tarFile("hardlink.tar",
+		entry{Name: "bin/tool", Mode: 0o755, Body: "#!/bin/sh"},
+		entry{Name: "tool", Link: "bin/tool", Hardlink: true},
+	)
+	if err != nil {
+		return err
+	}
+
+	contents, err := t.Tar.Extract(f).File("tool").Contents(ctx)
+	if err != nil {
+		return err
+	}
+
+	if contents != "#!/bin/sh" {
+		return errors.New("unexpected hardlink contents: " + contents)
+	}
+
+	return nil
+}
+
+func (t *Tar) ExtractCompressionTest(ctx context.Context) error {
+	testCases := map[dagger.ArchiveCompression][]string{
+		dagger.ArchiveCompressionGzip:  {"tar", "-czf"},
+		dagger.ArchiveCompressionZstd:  {"tar", "--zstd", "-cf"},
+		dagger.ArchiveCompressionXz:    {"tar", "-cJf"},
+		dagger.ArchiveCompressionBzip2: {"tar", "-cjf"},
+	}
+
+	ctr := dag.Container().
+		From("alpine").
+		WithExec([]string{"apk", "add", "tar", "zstd", "xz", "bzip2"}).
+		WithNewFile("/src/hello.txt", "hello world").
+		WithWorkdir("/src")
+
+	ep := pool.New().WithErrors().WithContext(ctx)
+
+	for compression, args := range testCases {
+		ep.Go(func(ctx context.Context) error {
+			// No file extension so compression must be detected from the contents.
+			f := ctr.
+				WithExec(append(args, "/archive", "hello.txt")).
+				File("/archive")
+
+			for _, opts := range []dagger.ArchiveTarExtractOpts{{}, {Compression: compression}} {
+				contents, err := t.Tar.Extract(f, opts).File("hello.txt").Contents(ctx)
+				if err != nil {
+					return fmt.Errorf("%s: %w", compression, err)
+				}
+
+				if contents != "hello world" {
+					return fmt.Errorf("%s: unexpected file contents: %s", compression, contents)
+				}
+			}
+
+			return nil
+		})
+	}
+
+	return ep.Wait()
+}
+
+func (t *Tar) ListTest(ctx context.Context) error {
+	f, err := tarFile("list.tar", validArchive...)
+	if err != nil {
+		return err
+	}
+
+	entries, err := t.Tar.List(ctx, f)
+	if err != nil {
+		return err
+	}
+
+	return checkListed(ctx, entries)
+}
+
+func (t *Tar) FileTest(ctx context.Context) error {
+	f, err := tarFile("file.tar", append(validArchive, entry{Name: "release/[glob]*", Mode: 0o644, Body: "glob"})...)
+	if err != nil {
+		return err
+	}
+
+	testCases := map[string]string{
+		"release/bin/tool": "#!/bin/sh",
+		"release/[glob]*":  "glob",
+	}
+
+	for name, expected := range testCases {
+		contents, err := t.Tar.File(f, name).Contents(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		if contents != expected {
+			return fmt.Errorf("%s: unexpected file contents: %s", name, contents)
+		}
+	}
+
+	_, err = t.Tar.File(f, "release/missing").Sync(ctx)
+	if err == nil {
+		return errors.New("expected missing file to fail")
+	}
+
+	return nil
+}
+
+func (t *Tar) CreateInvalidNameTest(ctx context.Context) error {
+	dir := dag.Directory().WithNewFile("hello.txt", "hello world")
+
+	for _, name := range []string{"../escape", "nested/archive", ".."} {
+		_, err := t.Tar.Create(dir, dagger.ArchiveTarCreateOpts{
+			Name: name,
+		}).Sync(ctx)
+		if err == nil {
+			return fmt.Errorf("expected create to reject archive name: %s", name)
+		}
+		if !strings.Contains(err.Error(), "invalid archive name") {
+			return fmt.Errorf("expected invalid archive name error for %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
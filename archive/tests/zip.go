@@ -7,7 +7,9 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"dagger/archive-tests/internal/dagger"
 
@@ -29,6 +31,15 @@ func (z *Zip) All(ctx context.Context) error {
 	ep := pool.New().WithErrors().WithContext(ctx)
 
 	ep.Go(z.FromUrlTest)
+	ep.Go(z.CreateTest)
+	ep.Go(z.CreateReproducibleTest)
+	ep.Go(z.CreateInvalidNameTest)
+	ep.Go(z.ExtractUnsafeTest)
+	ep.Go(z.ExtractModesTest)
+	ep.Go(z.ExtractStripComponentsTest)
+	ep.Go(z.ExtractIncludeTest)
+	ep.Go(z.ListTest)
+	ep.Go(z.FileTest)
 
 	return ep.Wait()
 }
@@ -49,3 +60,192 @@ func (z *Zip) FromUrlTest(ctx context.Context) error {
 
 	return nil
 }
+
+func (z *Zip) CreateTest(ctx context.Context) error {
+	dir := dag.Directory().
+		WithNewFile("hello.txt", "hello world").
+		WithNewFile("nested/bin", "#!/bin/sh", dagger.DirectoryWithNewFileOpts{
+			Permissions: 0o755,
+		})
+
+	f := z.Zip.Create(dir)
+
+	out := z.Zip.Extract(f)
+
+	contents, err := out.File("hello.txt").Contents(ctx)
+	if err != nil {
+		return err
+	}
+
+	if contents != "hello world" {
+		return errors.New("unexpected file contents after round trip: " + contents)
+	}
+
+	entries, err := out.Entries(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) != 2 {
+		return fmt.Errorf("expected 2 top level entries in directory instead of: %d", len(entries))
+	}
+
+	return nil
+}
+
+func (z *Zip) CreateReproducibleTest(ctx context.Context) error {
+	dir := dag.Directory().WithNewFile("hello.txt", "hello world")
+
+	opts := dagger.ArchiveZipCreateOpts{
+		Reproducible: true,
+	}
+
+	first, err := z.Zip.Create(dir.WithTimestamps(1000000000), opts).Digest(ctx, dagger.FileDigestOpts{
+		ExcludeMetadata: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	second, err := z.Zip.Create(dir.WithTimestamps(2000000000), opts).Digest(ctx, dagger.FileDigestOpts{
+		ExcludeMetadata: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	if first != second {
+		return fmt.Errorf("expected reproducible archives to be identical: %s != %s", first, second)
+	}
+
+	return nil
+}
+
+func (z *Zip) ExtractUnsafeTest(ctx context.Context) error {
+	return extractFails(ctx, func(name string, entries []entry) (*dagger.Directory, error) {
+		f, err := zipFile(name+".zip", entries...)
+		if err != nil {
+			return nil, err
+		}
+
+		return z.Zip.Extract(f), nil
+	})
+}
+
+func (z *Zip) ExtractModesTest(ctx context.Context) error {
+	f, err := zipFile("modes.zip", validArchive...)
+	if err != nil {
+		return err
+	}
+
+	out := z.Zip.Extract(f).Directory("release")
+
+	return checkExtracted(ctx, out)
+}
+
+func (z *Zip) ExtractStripComponentsTest(ctx context.Context) error {
+	f, err := zipFile("strip.zip", validArchive...)
+	if err != nil {
+		return err
+	}
+
+	out := z.Zip.Extract(f, dagger.ArchiveZipExtractOpts{
+		StripComponents: 1,
+	})
+
+	return checkExtracted(ctx, out)
+}
+
+func (z *Zip) ExtractIncludeTest(ctx context.Context) error {
+	f, err := zipFile("include.zip", validArchive...)
+	if err != nil {
+		return err
+	}
+
+	out := z.Zip.Extract(f, dagger.ArchiveZipExtractOpts{
+		StripComponents: 1,
+		Include:         []string{"bin"},
+	})
+
+	entries, err := out.Entries(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) != 1 {
+		return fmt.Errorf("expected only the bin directory to be extracted: %v", entries)
+	}
+
+	contents, err := out.File("bin/tool").Contents(ctx)
+	if err != nil {
+		return err
+	}
+
+	if contents != "#!/bin/sh" {
+		return errors.New("unexpected file contents: " + contents)
+	}
+
+	return nil
+}
+
+func (z *Zip) ListTest(ctx context.Context) error {
+	f, err := zipFile("list.zip", validArchive...)
+	if err != nil {
+		return err
+	}
+
+	entries, err := z.Zip.List(ctx, f)
+	if err != nil {
+		return err
+	}
+
+	return checkListed(ctx, entries)
+}
+
+func (z *Zip) FileTest(ctx context.Context) error {
+	f, err := zipFile("file.zip", append(validArchive, entry{Name: "release/[glob]*", Mode: 0o644, Body: "glob"})...)
+	if err != nil {
+		return err
+	}
+
+	testCases := map[string]string{
+		"release/bin/tool": "#!/bin/sh",
+		"release/[glob]*":  "glob",
+	}
+
+	for name, expected := range testCases {
+		contents, err := z.Zip.File(f, name).Contents(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		if contents != expected {
+			return fmt.Errorf("%s: unexpected file contents: %s", name, contents)
+		}
+	}
+
+	_, err = z.Zip.File(f, "release/missing").Sync(ctx)
+	if err == nil {
+		return errors.New("expected missing file to fail")
+	}
+
+	return nil
+}
+
+func (z *Zip) CreateInvalidNameTest(ctx context.Context) error {
+	dir := dag.Directory().WithNewFile("hello.txt", "hello world")
+
+	for _, name := range []string{"../escape", "nested/archive", ".."} {
+		_, err := z.Zip.Create(dir, dagger.ArchiveZipCreateOpts{
+			Name: name,
+		}).Sync(ctx)
+		if err == nil {
+			return fmt.Errorf("expected create to reject archive name: %s", name)
+		}
+		if !strings.Contains(err.Error(), "invalid archive name") {
+			return fmt.Errorf("expected invalid archive name error for %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
@@ -8,7 +8,6 @@ package main
 import (
 	"context"
 
-	"dagger/archive/internal/archive"
 	"dagger/archive/internal/dagger"
 )
 
@@ -21,22 +20,77 @@ func (m *Archive) Zip() *Zip {
 // Extract zip contents to a directory
 func (z *Zip) Extract(
 	ctx context.Context,
+
 	file *dagger.File,
+
+	// Number of leading path elements to remove from entry names.
+	// +optional
+	stripComponents int,
+
+	// Only extract entries matching these globs, after stripping.
+	// +optional
+	include []string,
 ) (*dagger.Directory, error) {
-	name, err := file.Name(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	_, err = file.Export(ctx, name)
-	if err != nil {
-		return nil, err
-	}
-
-	err = archive.ExtractZip(ctx, name, "out")
-	if err != nil {
-		return nil, err
-	}
-
-	return dag.CurrentModule().Workdir("out"), nil
+	return extract(ctx, file, extractArgs{
+		format:          "zip",
+		stripComponents: stripComponents,
+		include:         include,
+	})
+}
+
+// List the entries of a zip archive without extracting it.
+func (z *Zip) List(
+	ctx context.Context,
+	file *dagger.File,
+) ([]*Entry, error) {
+	return list(ctx, file, "zip", "")
+}
+
+// Extract a single file from a zip archive.
+func (z *Zip) File(
+	ctx context.Context,
+
+	file *dagger.File,
+
+	// Path of the file within the archive.
+	path string,
+) (*dagger.File, error) {
+	return extractFile(ctx, file, extractArgs{
+		format: "zip",
+	}, path)
+}
+
+// Create a zip archive from the contents of a directory.
+func (z *Zip) Create(
+	dir *dagger.Directory,
+
+	// Name of the created archive.
+	// +default="archive.zip"
+	name string,
+
+	// Only include paths matching these globs.
+	// +optional
+	include []string,
+
+	// Exclude paths matching these globs.
+	// +optional
+	exclude []string,
+
+	// Set the modification time of every entry to mtime.
+	// +optional
+	reproducible bool,
+
+	// Modification time of every entry, in seconds since the Unix epoch, when reproducible.
+	// Defaults to 1980-01-01, the earliest time a zip archive can represent.
+	// +default=315532800
+	mtime int,
+) (*dagger.File, error) {
+	return create(dir, createArgs{
+		format:       "zip",
+		name:         name,
+		include:      include,
+		exclude:      exclude,
+		reproducible: reproducible,
+		mtime:        mtime,
+	})
 }
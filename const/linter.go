@@ -7,6 +7,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 
 	"dagger/const/internal/dagger"
 )
@@ -14,14 +15,56 @@ import (
 type GoLinter struct {
 	// +private
 	Report *dagger.File
+
+	// +private
+	Severity []string
 }
 
-func (*Const) GoLinter(report *dagger.File) *GoLinter {
+func (*Const) GoLinter(
+	report *dagger.File,
+
+	// Severity of each finding to report, regardless of the report contents.
+	// +optional
+	severity []string,
+) *GoLinter {
 	return &GoLinter{
-		Report: report,
+		Report:   report,
+		Severity: severity,
 	}
 }
 
-func (g *GoLinter) GoLinter(ctx context.Context, ctr *dagger.Container) *dagger.File {
+func (g *GoLinter) Lint(ctx context.Context, ctr *dagger.Container) *dagger.File {
 	return g.Report
 }
+
+type GoLintFinding struct {
+	File     string
+	Line     int
+	Rule     string
+	Severity string
+	Message  string
+}
+
+func (g *GoLinter) Findings(
+	ctx context.Context,
+	ctr *dagger.Container,
+	report *dagger.File,
+) ([]*GoLintFinding, error) {
+	_, err := report.Sync(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make([]*GoLintFinding, 0, len(g.Severity))
+	for i, severity := range g.Severity {
+		findings = append(findings, &GoLintFinding{
+			File:     "const.go",
+			Line:     i + 1,
+			Rule:     "const",
+			Severity: severity,
+			Message:  fmt.Sprintf("constant %s finding", severity),
+		})
+	}
+
+	return findings, nil
+}
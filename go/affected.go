@@ -0,0 +1,290 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"path"
+	"slices"
+	"strings"
+
+	"dagger/go/internal/affected"
+	"dagger/go/internal/dagger"
+
+	"github.com/sourcegraph/conc/pool"
+)
+
+// gitImage is the image git is installed in to compare directories.
+const gitImage = "alpine:3.22"
+
+// Paths of the files which differ between a base directory and the
+// workspace, including files which were added or deleted.
+func (w *Workspace) Changes(ctx context.Context, base *dagger.Directory) ([]string, error) {
+	// git diff exits with 1 when there are differences.
+	out, err := dag.Container().
+		From(gitImage).
+		WithExec([]string{"apk", "add", "--no-cache", "git"}).
+		WithMountedDirectory("/diff/base", base).
+		WithMountedDirectory("/diff/head", w.Source).
+		WithWorkdir("/diff").
+		WithExec([]string{
+			"sh",
+			"-c",
+			"git diff --no-index --name-status --no-renames base head; test $? -le 1",
+		}).
+		Stdout(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for line := range strings.Lines(out) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		// Deleted files are only listed by their path in the base
+		// directory, so the status must be used instead of the names
+		// listed by --name-only which only include the head path.
+		_, name, found := strings.Cut(line, "\t")
+		if !found {
+			return nil, errors.New("unexpected git diff output: " + line)
+		}
+
+		_, file, found := strings.Cut(name, "/")
+		if !found {
+			return nil, errors.New("unexpected git diff output: " + line)
+		}
+
+		files = append(files, file)
+	}
+
+	slices.Sort(files)
+	return slices.Compact(files), nil
+}
+
+// AffectedModule
+type AffectedModule struct {
+	// Path of the module within the workspace.
+	Path string
+
+	// Import paths of the affected packages within the module.
+	Packages []string
+
+	// +private
+	Mod *Mod
+}
+
+// Affected
+type Affected struct {
+	// Modules with at least one affected package, in the same order as Paths.
+	Modules []*AffectedModule
+}
+
+// Determine the packages affected by changes, either directly or through
+// the packages they depend on, so only those need to be generated, tested
+// and linted.
+func (w *Workspace) Affected(
+	ctx context.Context,
+
+	// Source to compare the workspace to, e.g. the target branch of a pull request.
+	// +optional
+	base *dagger.Directory,
+
+	// Paths of changed files relative to the workspace, e.g. from git diff --name-only.
+	// +optional
+	changedFiles []string,
+) (*Affected, error) {
+	if base == nil && changedFiles == nil {
+		return nil, errors.New("either a base directory or changed files must be given")
+	}
+
+	files := changedFiles
+	if base != nil {
+		changes, err := w.Changes(ctx, base)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(slices.Clone(files), changes...)
+	}
+
+	paths, err := w.Paths(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ep := pool.New().WithErrors().WithContext(ctx)
+
+	modPkgs := make([][]affected.Package, len(paths))
+	for i, modPath := range paths {
+		ep.Go(func(ctx context.Context) error {
+			pkgs, err := w.Module(modPath).packages(ctx, modPath)
+			if err != nil {
+				return err
+			}
+
+			modPkgs[i] = pkgs
+			return nil
+		})
+	}
+
+	err = ep.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	// Packages are resolved across every module so changes in one module
+	// affect the packages importing it from other modules in the workspace.
+	affectedPkgs := affected.Packages(slices.Concat(modPkgs...), files)
+
+	result := &Affected{}
+	for i, modPath := range paths {
+		var pkgs []string
+		for _, p := range modPkgs[i] {
+			if slices.Contains(affectedPkgs, p.ImportPath) {
+				pkgs = append(pkgs, p.ImportPath)
+			}
+		}
+		if len(pkgs) == 0 {
+			continue
+		}
+
+		slices.Sort(pkgs)
+		result.Modules = append(result.Modules, &AffectedModule{
+			Path:     modPath,
+			Packages: pkgs,
+			Mod:      w.Module(modPath),
+		})
+	}
+
+	return result, nil
+}
+
+// packages lists every package of the module along with the packages it
+// and its tests depend on.
+func (m *Mod) packages(ctx context.Context, modPath string) ([]affected.Package, error) {
+	out, err := m.Ctr.
+		WithExec([]string{"go", "list", "-e", "-json=ImportPath,Dir,Module,Deps,TestImports,XTestImports,EmbedFiles,TestEmbedFiles,XTestEmbedFiles", "./..."}).
+		Stdout(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return affected.ParseList(strings.NewReader(out), "/src", path.Clean(modPath))
+}
+
+// The affected module.
+func (am *AffectedModule) Module() *Mod {
+	return am.Mod
+}
+
+// Validate no change to the filesystem after running generate directives
+// in the affected packages.
+func (am *AffectedModule) Generate(ctx context.Context) error {
+	return am.Mod.Generate(strings.Join(am.Packages, " ")).Diff(ctx)
+}
+
+// Run tests of the affected packages and return coverage report.
+func (am *AffectedModule) Test(
+	ctx context.Context,
+
+	// +default=true
+	race bool,
+) (*dagger.File, error) {
+	return am.Mod.Test(strings.Join(am.Packages, " "), race).Coverage(ctx, Atomic)
+}
+
+// Lint the affected module. Linters run on whole modules so findings are
+// not limited to the affected packages.
+func (am *AffectedModule) Lint(
+	ctx context.Context,
+
+	// +optional
+	linter Linter,
+
+	// Fail if any lint finding is at or above this severity.
+	// +optional
+	severity LintSeverity,
+) (*dagger.File, error) {
+	lib := am.Mod.Library(linter, nil, nil, nil)
+
+	report, err := lib.Lint(ctx).Sync(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if severity != "" {
+		err = lib.LintCheck(ctx, report, severity)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// Generate, test and lint the affected packages of every affected module
+// in parallel. Failing modules do not stop the others, see Check.
+func (a *Affected) Ci(
+	ctx context.Context,
+
+	// +optional
+	linter Linter,
+
+	// Fail if any lint finding is at or above this severity.
+	// +optional
+	lintSeverity LintSeverity,
+
+	// +default=true
+	race bool,
+) (*WorkspaceCiReport, error) {
+	ep := pool.New().WithContext(ctx)
+
+	report := &WorkspaceCiReport{
+		Modules: make([]*WorkspaceModuleResult, len(a.Modules)),
+	}
+	for i, am := range a.Modules {
+		ep.Go(func(ctx context.Context) error {
+			err := am.ci(ctx, linter, lintSeverity, race)
+
+			result := &WorkspaceModuleResult{
+				Path:   am.Path,
+				Passed: err == nil,
+			}
+			if err != nil {
+				result.Error = err.Error()
+			}
+
+			report.Modules[i] = result
+			return nil
+		})
+	}
+
+	err := ep.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (am *AffectedModule) ci(ctx context.Context, linter Linter, lintSeverity LintSeverity, race bool) error {
+	err := am.Generate(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = am.Test(ctx, race)
+	if err != nil {
+		return err
+	}
+
+	_, err = am.Lint(ctx, linter, lintSeverity)
+	return err
+}
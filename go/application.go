@@ -21,7 +21,33 @@ import (
 type ContainerScanner interface {
 	DaggerObject
 
-	ScanContainer(ctx context.Context, ctr *dagger.Container) error
+	// Scan a container and return a report which can be parsed by Vulnerabilities.
+	ScanContainer(ctr *dagger.Container) *dagger.File
+
+	// Parse a report returned by ScanContainer into individual vulnerabilities.
+	Vulnerabilities(
+		ctx context.Context,
+		report *dagger.File,
+	) ([]ContainerVulnerability, error)
+}
+
+type ContainerVulnerability interface {
+	DaggerObject
+
+	// Identifier of the vulnerability, e.g. CVE-2024-24790.
+	VulnerabilityID(ctx context.Context) (string, error)
+
+	// Name of the vulnerable package.
+	Package(ctx context.Context) (string, error)
+
+	// Version of the package which was scanned.
+	InstalledVersion(ctx context.Context) (string, error)
+
+	// Version(s) of the package fixing the vulnerability, if any.
+	FixedVersion(ctx context.Context) (string, error)
+
+	// Severity of the vulnerability, e.g. LOW or CRITICAL.
+	Severity(ctx context.Context) (string, error)
 }
 
 type Application struct {
@@ -33,6 +59,48 @@ type Application struct {
 
 	// +private
 	ContainerScanner ContainerScanner
+
+	// +private
+	SigningKey *dagger.Secret
+
+	// +private
+	SigningPassword *dagger.Secret
+
+	// +private
+	TlogUpload bool
+
+	// +private
+	RegistryService *dagger.Service
+
+	// +private
+	BaseImage string
+
+	// +private
+	BaseContainer *dagger.Container
+
+	// +private
+	Labels []string
+
+	// +private
+	Annotations []string
+
+	// +private
+	User string
+
+	// +private
+	ExposedPorts []int
+
+	// +private
+	EnvVariables []string
+
+	// +private
+	Workdir string
+
+	// +private
+	CaCertificates bool
+
+	// +private
+	Tzdata bool
 }
 
 // A set of functions for working with a application written in Go.
@@ -41,11 +109,12 @@ func (lib *Library) Application(
 	mainPackagePath string,
 
 	// Specify a tool for scanning built application container before publishing.
+	// Defaults to trivy, which fails on HIGH or CRITICAL vulnerabilities.
 	// +optional
 	containerScanner ContainerScanner,
 ) *Application {
 	if containerScanner == nil {
-		containerScanner = dag.Trivy()
+		containerScanner = trivyScanner{dag.Trivy()}
 	}
 
 	return &Application{
@@ -83,6 +152,17 @@ func (app *Application) Ci(
 
 	// +default=["linux/amd64","linux/arm64"]
 	platforms []dagger.Platform,
+
+	// Attach an SBOM for each platform variant to the published image.
+	// +optional
+	attachSbom bool,
+
+	// +default="cyclonedx"
+	sbomFormat SbomFormat,
+
+	// Attest SLSA provenance of the build, requires a signing key.
+	// +optional
+	attestProvenance bool,
 ) error {
 	err := app.Library.Ci(ctx)
 	if err != nil {
@@ -101,11 +181,23 @@ func (app *Application) Ci(
 		return err
 	}
 
-	err = app.Scan(ctx, variants)
+	scans, err := app.Scan(ctx, variants)
 	if err != nil {
 		return err
 	}
 
+	for _, scan := range scans {
+		fmt.Printf("scanned: %s (%d vulnerabilities)\n", scan.Platform, len(scan.Vulnerabilities))
+	}
+
+	var provenance *dagger.File
+	if attestProvenance {
+		provenance, err = app.Provenance(ctx, ldflags, buildTags, trimpath, enableCGO, platforms)
+		if err != nil {
+			return err
+		}
+	}
+
 	results, err := app.Publish(
 		ctx,
 		imageRegistry,
@@ -114,6 +206,9 @@ func (app *Application) Ci(
 		variants,
 		registryUsername,
 		registrySecret,
+		attachSbom,
+		sbomFormat,
+		provenance,
 	)
 	if err != nil {
 		return err
@@ -147,11 +242,13 @@ func (app *Application) Build(
 	// +default=["linux/amd64", "linux/arm64"]
 	platforms []dagger.Platform,
 ) ([]*dagger.Container, error) {
-	buildPool := pool.New().WithErrors().WithContext(ctx)
+	ep := pool.New().WithErrors().WithContext(ctx)
 
-	containerCh := make(chan *dagger.Container, len(platforms))
-	for _, platform := range platforms {
-		buildPool.Go(func(ctx context.Context) error {
+	// Containers are stored by index so variants are always returned
+	// in the same order as the platforms.
+	containers := make([]*dagger.Container, len(platforms))
+	for i, platform := range platforms {
+		ep.Go(func(ctx context.Context) error {
 			b, err := app.Library.Module.Build(
 				app.MainPackagePath,
 				false,
@@ -165,48 +262,17 @@ func (app *Application) Build(
 				return err
 			}
 
-			f := b.Output()
-
-			c := dag.Container(dagger.ContainerOpts{
-				Platform: platform,
-			}).
-				WithFile("/main", f).
-				WithEntrypoint([]string{"/main"})
-
-			select {
-			case <-ctx.Done():
-				return nil
-			case containerCh <- c:
+			c, err := app.image(ctx, platform, b.Output())
+			if err != nil {
+				return err
 			}
 
+			containers[i] = c
 			return nil
 		})
 	}
 
-	collectPool := pool.New().WithErrors().WithContext(ctx)
-	collectPool.Go(func(ctx context.Context) error {
-		defer close(containerCh)
-
-		return buildPool.Wait()
-	})
-
-	var containers []*dagger.Container
-	collectPool.Go(func(ctx context.Context) error {
-		for {
-			select {
-			case <-ctx.Done():
-				return nil
-			case c, ok := <-containerCh:
-				if !ok {
-					return nil
-				}
-
-				containers = append(containers, c)
-			}
-		}
-	})
-
-	err := collectPool.Wait()
+	err := ep.Wait()
 	if err != nil {
 		return nil, err
 	}
@@ -258,24 +324,131 @@ func (app *Application) AsService(
 	return svc, nil
 }
 
+// Vulnerability
+type Vulnerability struct {
+	// Identifier of the vulnerability, e.g. CVE-2024-24790.
+	VulnerabilityID string
+
+	// Name of the vulnerable package.
+	Package string
+
+	// Version of the package which was scanned.
+	InstalledVersion string
+
+	// Version(s) of the package fixing the vulnerability, if any.
+	FixedVersion string
+
+	// Severity of the vulnerability, e.g. LOW or CRITICAL.
+	Severity string
+}
+
+// ScanResult
+type ScanResult struct {
+	// Platform of the scanned container.
+	Platform dagger.Platform
+
+	// Report returned by the container scanner.
+	Report *dagger.File
+
+	Vulnerabilities []*Vulnerability
+}
+
 // Scan application container image(s).
 func (app *Application) Scan(
 	ctx context.Context,
 	platformVariants []*dagger.Container,
-) error {
+) ([]*ScanResult, error) {
 	if app.ContainerScanner == nil {
-		return nil
+		return []*ScanResult{}, nil
 	}
 
 	ep := pool.New().WithErrors().WithContext(ctx)
 
-	for _, ctr := range platformVariants {
+	results := make([]*ScanResult, len(platformVariants))
+	for i, ctr := range platformVariants {
 		ep.Go(func(ctx context.Context) error {
-			return app.ContainerScanner.ScanContainer(ctx, ctr)
+			result, err := app.scan(ctx, ctr)
+			if err != nil {
+				return err
+			}
+
+			results[i] = result
+			return nil
 		})
 	}
 
-	return ep.Wait()
+	err := ep.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (app *Application) scan(ctx context.Context, ctr *dagger.Container) (*ScanResult, error) {
+	platform, err := ctr.Platform(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := app.ContainerScanner.ScanContainer(ctr)
+
+	vulns, err := app.ContainerScanner.Vulnerabilities(ctx, report)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ScanResult{
+		Platform:        platform,
+		Report:          report,
+		Vulnerabilities: make([]*Vulnerability, 0, len(vulns)),
+	}
+	for _, vuln := range vulns {
+		v, err := toVulnerability(ctx, vuln)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Vulnerabilities = append(result.Vulnerabilities, v)
+	}
+
+	return result, nil
+}
+
+func toVulnerability(ctx context.Context, vuln ContainerVulnerability) (*Vulnerability, error) {
+	id, err := vuln.VulnerabilityID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg, err := vuln.Package(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	installed, err := vuln.InstalledVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fixed, err := vuln.FixedVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	severity, err := vuln.Severity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Vulnerability{
+		VulnerabilityID:  id,
+		Package:          pkg,
+		InstalledVersion: installed,
+		FixedVersion:     fixed,
+		Severity:         severity,
+	}
+	return v, nil
 }
 
 type PublishResult struct {
@@ -283,6 +456,10 @@ type PublishResult struct {
 	ImageName string `json:"image_name"`
 	Tag       string `json:"tag"`
 	Digest    string `json:"digest"`
+
+	// Platforms included in the published image index, in the same
+	// order as the platform variants.
+	Platforms []dagger.Platform `json:"platforms"`
 }
 
 // Format publish result as a string.
@@ -321,21 +498,50 @@ func (app *Application) Publish(
 	registryUsername string,
 
 	registrySecret *dagger.Secret,
+
+	// Attach an SBOM for each platform variant to the published image
+	// as an OCI artifact.
+	// +optional
+	attachSbom bool,
+
+	// +default="cyclonedx"
+	sbomFormat SbomFormat,
+
+	// SLSA provenance predicate, e.g. from Provenance, to attest for the
+	// published image. Requires a signing key.
+	// +optional
+	provenance *dagger.File,
 ) ([]*PublishResult, error) {
-	publishPool := pool.New().WithErrors().WithContext(ctx)
+	if provenance != nil && app.SigningKey == nil {
+		return nil, errors.New("attesting provenance requires a signing key")
+	}
 
-	publishResultCh := make(chan *PublishResult, len(imageTags))
-	for _, tag := range imageTags {
+	platforms := make([]dagger.Platform, len(platformVariants))
+	for i, ctr := range platformVariants {
+		platform, err := ctr.Platform(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		platforms[i] = platform
+	}
+
+	// Pushes are not cancelled when another tag fails to publish so every
+	// tag is attempted and all failures are reported together.
+	ep := pool.New().WithErrors()
+
+	results := make([]*PublishResult, len(imageTags))
+	for i, tag := range imageTags {
 		addr := fmt.Sprintf("%s/%s:%s", registry, imageName, tag)
 
-		publishPool.Go(func(ctx context.Context) error {
-			fqin, err := dag.Container().
+		ep.Go(func() error {
+			fqin, err := app.withRegistry(dag.Container(), registry).
 				WithRegistryAuth(registry, registryUsername, registrySecret).
 				Publish(ctx, addr, dagger.ContainerPublishOpts{
 					PlatformVariants: platformVariants,
 				})
 			if err != nil {
-				return err
+				return fmt.Errorf("failed to publish %s: %w", addr, err)
 			}
 
 			_, digest, found := strings.Cut(fqin, "@")
@@ -343,49 +549,50 @@ func (app *Application) Publish(
 				return errors.New("malformed publish result: " + fqin)
 			}
 
-			result := &PublishResult{
+			results[i] = &PublishResult{
 				Registry:  registry,
 				ImageName: imageName,
 				Tag:       tag,
 				Digest:    digest,
+				Platforms: platforms,
 			}
-
-			select {
-			case <-ctx.Done():
-				return nil
-			case publishResultCh <- result:
-			}
-
 			return nil
 		})
 	}
 
-	collectPool := pool.New().WithErrors().WithContext(ctx)
-	collectPool.Go(func(ctx context.Context) error {
-		defer close(publishResultCh)
+	err := ep.Wait()
+	if err != nil {
+		return nil, err
+	}
 
-		return publishPool.Wait()
-	})
+	if !attachSbom && app.SigningKey == nil {
+		return results, nil
+	}
 
-	results := make([]*PublishResult, len(imageTags))
-	collectPool.Go(func(ctx context.Context) error {
-		for {
-			select {
-			case <-ctx.Done():
-				return nil
-			case result, ok := <-publishResultCh:
-				if !ok {
-					return nil
-				}
-
-				results = append(results, result)
+	// Every tag refers to the same image index so only process each digest once.
+	done := make(map[string]bool)
+	for _, result := range results {
+		if done[result.Digest] {
+			continue
+		}
+
+		ref := fmt.Sprintf("%s/%s@%s", registry, imageName, result.Digest)
+
+		if attachSbom {
+			err = app.attachSboms(ctx, ref, registry, platformVariants, registryUsername, registrySecret, sbomFormat)
+			if err != nil {
+				return nil, err
 			}
 		}
-	})
 
-	err := collectPool.Wait()
-	if err != nil {
-		return nil, err
+		if app.SigningKey != nil {
+			err = app.sign(ctx, ref, registry, registryUsername, registrySecret, provenance)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		done[result.Digest] = true
 	}
 
 	return results, nil
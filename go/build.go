@@ -6,11 +6,14 @@
 package main
 
 import (
+	"context"
+	"path"
 	"strings"
 
 	"dagger/go/internal/dagger"
 
 	"github.com/containerd/platforms"
+	"github.com/sourcegraph/conc/pool"
 )
 
 type Build struct {
@@ -66,6 +69,14 @@ func (m *Mod) Build(
 		ctr = ctr.
 			WithEnvVariable("GOARCH", p.Architecture).
 			WithEnvVariable("GOOS", p.OS)
+
+		switch {
+		case p.Variant == "":
+		case p.Architecture == "arm":
+			ctr = ctr.WithEnvVariable("GOARM", strings.TrimPrefix(p.Variant, "v"))
+		case p.Architecture == "amd64":
+			ctr = ctr.WithEnvVariable("GOAMD64", p.Variant)
+		}
 	}
 
 	cgoEnabled := "0"
@@ -121,3 +132,118 @@ func (b *Build) Output() *dagger.File {
 		WithExec(args).
 		File("/tmp/main")
 }
+
+// Build a Go module for multiple platforms.
+//
+// Binaries are named <name>_<os>_<arch>[.exe] with the architecture
+// variant, if any, appended to the architecture e.g. app_linux_armv7.
+func (m *Mod) BuildMatrix(
+	ctx context.Context,
+
+	pkg string,
+
+	// Name of the binary. Defaults to the last element of the package import path.
+	// +optional
+	name string,
+
+	// +optional
+	race bool,
+
+	// +optional
+	ldflags []string,
+
+	// +optional
+	tags []string,
+
+	// +optional
+	trimpath bool,
+
+	// +optional
+	enableCGO bool,
+
+	// +default=["linux/amd64", "linux/arm64", "darwin/amd64", "darwin/arm64", "windows/amd64"]
+	platforms []dagger.Platform,
+) (*dagger.Directory, error) {
+	if name == "" {
+		var err error
+		name, err = m.binaryName(ctx, pkg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	binaries := make([]*dagger.File, len(platforms))
+	names := make([]string, len(platforms))
+
+	buildPool := pool.New().WithErrors().WithContext(ctx)
+	for i, platform := range platforms {
+		buildPool.Go(func(ctx context.Context) error {
+			fileName, err := platformBinaryName(name, platform)
+			if err != nil {
+				return err
+			}
+
+			b, err := m.Build(
+				pkg,
+				race,
+				ldflags,
+				tags,
+				trimpath,
+				enableCGO,
+				platform,
+			)
+			if err != nil {
+				return err
+			}
+
+			f, err := b.Output().Sync(ctx)
+			if err != nil {
+				return err
+			}
+
+			binaries[i] = f
+			names[i] = fileName
+
+			return nil
+		})
+	}
+
+	err := buildPool.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	dir := dag.Directory()
+	for i, f := range binaries {
+		dir = dir.WithFile(names[i], f)
+	}
+
+	return dir, nil
+}
+
+// binaryName returns the default name of the binary built from pkg, which
+// is the last element of its import path, just like go build.
+func (m *Mod) binaryName(ctx context.Context, pkg string) (string, error) {
+	importPath, err := m.Ctr.
+		WithExec([]string{"go", "list", "-f", "{{.ImportPath}}", pkg}).
+		Stdout(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return path.Base(strings.TrimSpace(importPath)), nil
+}
+
+func platformBinaryName(name string, platform dagger.Platform) (string, error) {
+	p, err := platforms.Parse(string(platform))
+	if err != nil {
+		return "", err
+	}
+
+	fileName := name + "_" + p.OS + "_" + p.Architecture + p.Variant
+	if p.OS == "windows" {
+		fileName += ".exe"
+	}
+
+	return fileName, nil
+}
@@ -0,0 +1,202 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"dagger/go/internal/coverprofile"
+	"dagger/go/internal/dagger"
+)
+
+// Coverage
+type Coverage struct {
+	// +private
+	Ctr *dagger.Container
+
+	// +private
+	Profile *dagger.File
+}
+
+// Analyze a coverage profile produced by running tests within the module.
+func (m *Mod) Coverage(profile *dagger.File) *Coverage {
+	return &Coverage{
+		Ctr:     m.Ctr,
+		Profile: profile,
+	}
+}
+
+// CoverageSummary
+type CoverageSummary struct {
+	// Percentage of statements covered across all packages.
+	Total float64
+
+	// Coverage of each package, sorted by import path.
+	Packages []*CoverageStats
+
+	// Coverage of each file, sorted by file name.
+	Files []*CoverageStats
+}
+
+// CoverageStats
+type CoverageStats struct {
+	// Import path of a package or file.
+	Name string
+
+	// Number of statements.
+	Statements int
+
+	// Number of statements executed at least once.
+	Covered int
+
+	// Percentage of statements covered.
+	Percent float64
+}
+
+func newCoverageStats(name string, s coverprofile.Stats) *CoverageStats {
+	return &CoverageStats{
+		Name:       name,
+		Statements: s.Statements,
+		Covered:    s.Covered,
+		Percent:    s.Percent(),
+	}
+}
+
+func (c *Coverage) profiles(ctx context.Context) ([]*coverprofile.Profile, error) {
+	contents, err := c.Profile.Contents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return coverprofile.Parse(strings.NewReader(contents))
+}
+
+// Summarize coverage by package and file.
+func (c *Coverage) Summary(ctx context.Context) (*CoverageSummary, error) {
+	profiles, err := c.profiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &CoverageSummary{
+		Total: coverprofile.Total(profiles).Percent(),
+	}
+
+	for _, pkg := range coverprofile.ByPackage(profiles) {
+		summary.Packages = append(summary.Packages, newCoverageStats(pkg.Name, pkg.Stats))
+	}
+
+	for _, p := range profiles {
+		summary.Files = append(summary.Files, newCoverageStats(p.FileName, p.Stats()))
+	}
+
+	return summary, nil
+}
+
+// Render the coverage profile as HTML.
+func (c *Coverage) Html() *dagger.File {
+	return c.Ctr.
+		WithMountedFile("/tmp/cover.out", c.Profile).
+		WithExec([]string{"go", "tool", "cover", "-html", "/tmp/cover.out", "-o", "/tmp/coverage.html"}).
+		File("/tmp/coverage.html")
+}
+
+// Render the coverage profile as Cobertura XML.
+func (c *Coverage) Cobertura(
+	ctx context.Context,
+
+	// Unix time in seconds recorded as the report timestamp, e.g.
+	// SOURCE_DATE_EPOCH, defaults to 0 so the same profile always
+	// renders the same report.
+	// +optional
+	timestamp int,
+) (*dagger.File, error) {
+	profiles, err := c.profiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workdir, err := c.Ctr.Workdir(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	module, err := c.modulePath(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := coverprofile.Cobertura(profiles, module, path.Clean(workdir), time.Unix(int64(timestamp), 0))
+	if err != nil {
+		return nil, err
+	}
+
+	return dag.File("cobertura.xml", string(b)), nil
+}
+
+// modulePath reads the module path from go.mod, since go list -m lists
+// every module of the workspace when run within one.
+func (c *Coverage) modulePath(ctx context.Context) (string, error) {
+	out, err := c.Ctr.WithExec([]string{"go", "mod", "edit", "-json"}).Stdout(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var mod struct {
+		Module struct {
+			Path string
+		}
+	}
+	err = json.Unmarshal([]byte(out), &mod)
+	if err != nil {
+		return "", err
+	}
+
+	return mod.Module.Path, nil
+}
+
+// Validate coverage meets the given minimum percentages.
+func (c *Coverage) Check(
+	ctx context.Context,
+
+	// Minimum percentage of statements covered across all packages.
+	// +optional
+	minimum float64,
+
+	// Minimum percentage of statements covered within each package.
+	// +optional
+	packageMinimum float64,
+) error {
+	summary, err := c.Summary(ctx)
+	if err != nil {
+		return err
+	}
+
+	var failures []string
+	if summary.Total < minimum {
+		failures = append(failures, fmt.Sprintf("total coverage %.1f%% is below minimum of %.1f%%", summary.Total, minimum))
+	}
+
+	for _, pkg := range summary.Packages {
+		if pkg.Percent >= packageMinimum {
+			continue
+		}
+
+		failures = append(failures, fmt.Sprintf("package %s coverage %.1f%% is below minimum of %.1f%%", pkg.Name, pkg.Percent, packageMinimum))
+	}
+
+	if len(failures) > 0 {
+		return errors.New("insufficient test coverage:\n" + strings.Join(failures, "\n"))
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+
+	"dagger/go/internal/dagger"
+)
+
+// The generated clients of the default implementations return their own
+// concrete object types from list functions so they cannot satisfy the
+// interfaces directly. These adapters embed the clients, keeping their
+// object identity, and only convert the list results.
+
+type noopLinter struct {
+	*dagger.NoopGoLinter
+}
+
+func (l noopLinter) Findings(
+	ctx context.Context,
+	ctr *dagger.Container,
+	report *dagger.File,
+) ([]LintFinding, error) {
+	findings, err := l.NoopGoLinter.Findings(ctx, ctr, report)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]LintFinding, len(findings))
+	for i := range findings {
+		out[i] = &findings[i]
+	}
+
+	return out, nil
+}
+
+type trivyScanner struct {
+	*dagger.Trivy
+}
+
+func (s trivyScanner) Vulnerabilities(
+	ctx context.Context,
+	report *dagger.File,
+) ([]ContainerVulnerability, error) {
+	vulns, err := s.Trivy.Vulnerabilities(ctx, report)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]ContainerVulnerability, len(vulns))
+	for i := range vulns {
+		out[i] = &vulns[i]
+	}
+
+	return out, nil
+}
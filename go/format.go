@@ -0,0 +1,163 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"dagger/go/internal/dagger"
+)
+
+type Formatter string
+
+const (
+	Gofmt     Formatter = "gofmt"
+	Goimports Formatter = "goimports"
+	Gofumpt   Formatter = "gofumpt"
+)
+
+// formatterVersions pins the version of each formatter which must be
+// installed, gofmt ships with the go toolchain.
+var formatterVersions = map[Formatter]string{
+	Goimports: "v0.34.0",
+	Gofumpt:   "v0.8.0",
+}
+
+type Format struct {
+	// +private
+	Ctr *dagger.Container
+
+	// +private
+	Cmd []string
+}
+
+// Format source code within a Go module.
+func (m *Mod) Format(
+	// +default="gofmt"
+	formatter Formatter,
+
+	// Put imports beginning with this prefix after third-party imports,
+	// only supported by goimports.
+	// +optional
+	localPrefix string,
+
+	// Version of goimports or gofumpt to install, defaults to a pinned
+	// version of the formatter.
+	// +optional
+	version string,
+) (*Format, error) {
+	if localPrefix != "" && formatter != Goimports {
+		return nil, fmt.Errorf("local prefix is not supported by %s", formatter)
+	}
+	if version == "" {
+		version = formatterVersions[formatter]
+	}
+
+	ctr := m.Ctr
+	cmd := []string{string(formatter), "-l", "-w"}
+
+	switch formatter {
+	case Gofmt:
+	case Goimports:
+		ctr = ctr.WithExec([]string{"go", "install", "golang.org/x/tools/cmd/goimports@" + version})
+		if localPrefix != "" {
+			cmd = append(cmd, "-local", localPrefix)
+		}
+	case Gofumpt:
+		ctr = ctr.WithExec([]string{"go", "install", "mvdan.cc/gofumpt@" + version})
+	default:
+		return nil, fmt.Errorf("unsupported formatter: %s", formatter)
+	}
+
+	f := &Format{
+		Ctr: ctr,
+		Cmd: cmd,
+	}
+	return f, nil
+}
+
+// run formats every Go file in place, skipping directories the go
+// command ignores, and records the names of the files which changed.
+func (f *Format) run() *dagger.Container {
+	script := `find . -type d \( -name vendor -o -name testdata -o -name '.?*' -o -name '_*' \) -prune -o -type f -name '*.go' -print0 | xargs -0 -r "$@"`
+
+	return f.Ctr.WithExec(
+		append([]string{"sh", "-c", script, "sh"}, f.Cmd...),
+		dagger.ContainerWithExecOpts{
+			RedirectStdout: "/tmp/format.out",
+		},
+	)
+}
+
+// Return the files which are not formatted, relative to the module.
+func (f *Format) Files(ctx context.Context) ([]string, error) {
+	out, err := f.run().File("/tmp/format.out").Contents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for line := range strings.Lines(out) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		files = append(files, strings.TrimPrefix(line, "./"))
+	}
+
+	slices.Sort(files)
+	return files, nil
+}
+
+// Return a unified diff of the changes needed to format the module.
+func (f *Format) Diff(ctx context.Context) (string, error) {
+	before := f.Ctr.Directory(".")
+	after := f.Apply()
+
+	// diff exits with 1 when there are differences. Only flags shared by
+	// GNU and busybox diff are used since the go image may provide either.
+	return f.Ctr.
+		WithMountedDirectory("/tmp/diff/a", before).
+		WithMountedDirectory("/tmp/diff/b", after).
+		WithWorkdir("/tmp/diff").
+		WithExec([]string{"sh", "-c", "diff -rN -U 3 a b; test $? -le 1"}).
+		Stdout(ctx)
+}
+
+// Return the module directory with every file formatted, e.g. to export
+// over the local copy of the module.
+func (f *Format) Apply() *dagger.Directory {
+	return f.run().Directory(".")
+}
+
+// Validate every file is formatted, listing the unformatted files along
+// with the changes needed to format them.
+func (f *Format) Check(ctx context.Context) error {
+	files, err := f.Files(ctx)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	diff, err := f.Diff(ctx)
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf(
+		"found %d file(s) not formatted by %s:\n%s\n\n%s",
+		len(files),
+		f.Cmd[0],
+		strings.Join(files, "\n"),
+		diff,
+	)
+}
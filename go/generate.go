@@ -25,7 +25,8 @@ type Generate struct {
 
 // Run commands described by directives within existing files.
 func (m *Mod) Generate(
-	// Package path to search for directives within.
+	// Package path to search for directives within, or multiple paths
+	// separated by spaces.
 	pkg string,
 ) *Generate {
 	return &Generate{
@@ -41,7 +42,7 @@ func (g *Generate) run(
 ) (string, error) {
 	cmd := []string{"go", "generate"}
 	cmd = append(cmd, args...)
-	cmd = append(cmd, pkg)
+	cmd = append(cmd, strings.Fields(pkg)...)
 
 	g.Ctr = g.Ctr.WithExec(cmd)
 
@@ -100,3 +101,22 @@ func (g *Generate) DryRun(ctx context.Context) ([]string, error) {
 	cmds := slices.Collect(strings.Lines(stdout))
 	return cmds, nil
 }
+
+// Return the module directory after running all generate directives, e.g.
+// to export over the local copy of the module.
+func (g *Generate) Apply(
+	// Only return the files which were added or changed by the directives.
+	// +optional
+	changedOnly bool,
+) *dagger.Directory {
+	cmd := []string{"go", "generate"}
+	cmd = append(cmd, strings.Fields(g.Pkg)...)
+
+	before := g.Ctr.Directory(".")
+	after := g.Ctr.WithExec(cmd).Directory(".")
+	if changedOnly {
+		return before.Diff(after)
+	}
+
+	return after
+}
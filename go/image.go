@@ -0,0 +1,251 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"dagger/go/internal/dagger"
+)
+
+// Build application images from a base image instead of scratch.
+func (app *Application) WithBaseImage(
+	// Either scratch, distroless, alpine or the reference of any other
+	// image, which is pulled for each platform being built.
+	image string,
+) *Application {
+	app.BaseImage = image
+	app.BaseContainer = nil
+	return app
+}
+
+// Build application images from a container, e.g. with additional
+// packages installed. Only platforms matching the container can be built.
+func (app *Application) WithBaseContainer(ctr *dagger.Container) *Application {
+	app.BaseImage = ""
+	app.BaseContainer = ctr
+	return app
+}
+
+// Set the standard OCI labels and annotations describing the image.
+func (app *Application) WithMetadata(
+	// URL of the source code, e.g. https://github.com/z5labs/daggerverse.
+	// +optional
+	source string,
+
+	// Revision of the source code, e.g. a git commit.
+	// +optional
+	revision string,
+
+	// Version of the application.
+	// +optional
+	version string,
+
+	// Creation time of the image in RFC 3339 format. Omitted by default
+	// so images are reproducible.
+	// +optional
+	created string,
+) *Application {
+	metadata := []struct {
+		name  string
+		value string
+	}{
+		{"org.opencontainers.image.source", source},
+		{"org.opencontainers.image.revision", revision},
+		{"org.opencontainers.image.version", version},
+		{"org.opencontainers.image.created", created},
+	}
+	for _, m := range metadata {
+		if m.value == "" {
+			continue
+		}
+
+		app.Labels = append(app.Labels, m.name+"="+m.value)
+		app.Annotations = append(app.Annotations, m.name+"="+m.value)
+	}
+	return app
+}
+
+// Add a label to the image config.
+func (app *Application) WithLabel(name string, value string) (*Application, error) {
+	label, err := keyValue("label", name, value)
+	if err != nil {
+		return nil, err
+	}
+
+	app.Labels = append(app.Labels, label)
+	return app, nil
+}
+
+// Add an annotation to the image manifest.
+func (app *Application) WithAnnotation(name string, value string) (*Application, error) {
+	annotation, err := keyValue("annotation", name, value)
+	if err != nil {
+		return nil, err
+	}
+
+	app.Annotations = append(app.Annotations, annotation)
+	return app, nil
+}
+
+// Run the application as the given user.
+func (app *Application) WithUser(
+	// User name or uid, optionally followed by a group, e.g. 65532:65532.
+	// +default="65532:65532"
+	user string,
+) *Application {
+	app.User = user
+	return app
+}
+
+// Expose a port from the image.
+func (app *Application) WithExposedPort(port int) *Application {
+	app.ExposedPorts = append(app.ExposedPorts, port)
+	return app
+}
+
+// Set an environment variable in the image.
+func (app *Application) WithEnvVariable(name string, value string) (*Application, error) {
+	env, err := keyValue("environment variable", name, value)
+	if err != nil {
+		return nil, err
+	}
+
+	app.EnvVariables = append(app.EnvVariables, env)
+	return app, nil
+}
+
+// keyValue joins a name and value as they are stored until the image is
+// built, rejecting names which could not be split back apart.
+func keyValue(kind string, name string, value string) (string, error) {
+	if name == "" || strings.Contains(name, "=") {
+		return "", fmt.Errorf("invalid %s name, must be non-empty and not contain '=': %q", kind, name)
+	}
+	return name + "=" + value, nil
+}
+
+// splitKeyValue splits a name=value pair stored by keyValue.
+func splitKeyValue(kind string, s string) (string, string, error) {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return "", "", fmt.Errorf("invalid %s, expected name=value: %q", kind, s)
+	}
+	return name, value, nil
+}
+
+// Set the working directory of the application.
+func (app *Application) WithWorkdir(path string) *Application {
+	app.Workdir = path
+	return app
+}
+
+// Include CA certificates so the application can make TLS connections.
+func (app *Application) WithCaCertificates() *Application {
+	app.CaCertificates = true
+	return app
+}
+
+// Include timezone data so the application can load locations other than UTC.
+func (app *Application) WithTzdata() *Application {
+	app.Tzdata = true
+	return app
+}
+
+// base returns the container the binary is added to for the platform.
+func (app *Application) base(ctx context.Context, platform dagger.Platform) (*dagger.Container, error) {
+	if app.BaseContainer != nil {
+		p, err := app.BaseContainer.Platform(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if p != platform {
+			return nil, fmt.Errorf("base container platform %s does not match %s", p, platform)
+		}
+
+		return app.BaseContainer, nil
+	}
+
+	ctr := dag.Container(dagger.ContainerOpts{
+		Platform: platform,
+	})
+
+	switch app.BaseImage {
+	case "", "scratch":
+		return ctr, nil
+	case "distroless":
+		return ctr.From("gcr.io/distroless/static-debian12"), nil
+	case "alpine":
+		return ctr.From("alpine:3"), nil
+	default:
+		return ctr.From(app.BaseImage), nil
+	}
+}
+
+// image packages the application binary into a container for the platform.
+func (app *Application) image(ctx context.Context, platform dagger.Platform, bin *dagger.File) (*dagger.Container, error) {
+	ctr, err := app.base(ctx, platform)
+	if err != nil {
+		return nil, err
+	}
+
+	// CA certificates and timezone data are platform independent so
+	// they are always taken from an alpine image for the engine platform.
+	if app.CaCertificates || app.Tzdata {
+		pkgs := dag.Container().
+			From("alpine:3").
+			WithExec([]string{"apk", "add", "--no-cache", "ca-certificates", "tzdata"})
+
+		if app.CaCertificates {
+			ctr = ctr.WithFile("/etc/ssl/certs/ca-certificates.crt", pkgs.File("/etc/ssl/certs/ca-certificates.crt"))
+		}
+		if app.Tzdata {
+			ctr = ctr.WithDirectory("/usr/share/zoneinfo", pkgs.Directory("/usr/share/zoneinfo"))
+		}
+	}
+
+	for _, label := range app.Labels {
+		name, value, err := splitKeyValue("label", label)
+		if err != nil {
+			return nil, err
+		}
+		ctr = ctr.WithLabel(name, value)
+	}
+	for _, annotation := range app.Annotations {
+		name, value, err := splitKeyValue("annotation", annotation)
+		if err != nil {
+			return nil, err
+		}
+		ctr = ctr.WithAnnotation(name, value)
+	}
+	for _, env := range app.EnvVariables {
+		name, value, err := splitKeyValue("environment variable", env)
+		if err != nil {
+			return nil, err
+		}
+		ctr = ctr.WithEnvVariable(name, value)
+	}
+	for _, port := range app.ExposedPorts {
+		ctr = ctr.WithExposedPort(port)
+	}
+
+	if app.Workdir != "" {
+		ctr = ctr.WithWorkdir(app.Workdir)
+	}
+
+	ctr = ctr.
+		WithFile("/main", bin, dagger.ContainerWithFileOpts{
+			Permissions: 0o755,
+		}).
+		WithEntrypoint([]string{"/main"})
+
+	if app.User != "" {
+		ctr = ctr.WithUser(app.User)
+	}
+
+	return ctr, nil
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// Package affected determines which Go packages are affected by changes
+// to files, either directly or through the packages they depend on.
+package affected
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Package is a Go package as listed by go list.
+type Package struct {
+	ImportPath string
+
+	// Directory of the package relative to the workspace root.
+	Dir string
+
+	// Directory of the module containing the package relative to the
+	// workspace root.
+	Module string
+
+	// Import path of the module containing the package.
+	ModulePath string
+
+	// Every package the package or its tests import, either directly
+	// or through other packages.
+	Imports []string
+
+	// Files embedded by the package or its tests relative to the
+	// workspace root.
+	EmbedFiles []string
+}
+
+type listPackage struct {
+	ImportPath      string
+	Dir             string
+	Deps            []string
+	TestImports     []string
+	XTestImports    []string
+	EmbedFiles      []string
+	TestEmbedFiles  []string
+	XTestEmbedFiles []string
+	Module          *struct {
+		Path string
+	}
+}
+
+// ParseList parses the output of go list -json run for a module in
+// the module directory. Package directories are made relative to root.
+func ParseList(r io.Reader, root string, module string) ([]Package, error) {
+	dec := json.NewDecoder(r)
+
+	var pkgs []Package
+	for {
+		var p listPackage
+		err := dec.Decode(&p)
+		if errors.Is(err, io.EOF) {
+			return pkgs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		dir := strings.TrimPrefix(strings.TrimPrefix(p.Dir, root), "/")
+		if dir == "" {
+			dir = "."
+		}
+
+		imports := slices.Concat(p.Deps, p.TestImports, p.XTestImports)
+		slices.Sort(imports)
+
+		var embeds []string
+		for _, f := range slices.Concat(p.EmbedFiles, p.TestEmbedFiles, p.XTestEmbedFiles) {
+			embeds = append(embeds, path.Join(dir, f))
+		}
+
+		var modulePath string
+		if p.Module != nil {
+			modulePath = p.Module.Path
+		}
+
+		pkgs = append(pkgs, Package{
+			ImportPath: p.ImportPath,
+			Dir:        dir,
+			Module:     module,
+			ModulePath: modulePath,
+			Imports:    slices.Compact(imports),
+			EmbedFiles: embeds,
+		})
+	}
+}
+
+// Packages returns the import paths of the packages affected by changes to
+// the files, given relative to the workspace root, sorted by import path.
+//
+// A package is changed if a file it embeds changes, or a file within its
+// directory changes, including in subdirectories such as testdata which
+// are not packages themselves. Every package of a module is changed if its
+// go.mod or go.sum changes, and changes to go.work affect every package.
+// A package is affected if it is changed or imports an affected package,
+// including through its tests, so deleting a package affects the packages
+// still importing it.
+func Packages(pkgs []Package, files []string) []string {
+	byDir := make(map[string]Package, len(pkgs))
+	embeddedBy := make(map[string][]string)
+	modules := make(map[string]string)
+	for _, p := range pkgs {
+		byDir[p.Dir] = p
+		modules[p.Module] = p.ModulePath
+
+		for _, f := range p.EmbedFiles {
+			embeddedBy[f] = append(embeddedBy[f], p.ImportPath)
+		}
+	}
+
+	affected := make(map[string]bool)
+	for _, file := range files {
+		file = path.Clean(strings.TrimPrefix(file, "/"))
+		dir, name := path.Split(file)
+		dir = path.Clean(dir)
+
+		switch name {
+		case "go.work", "go.work.sum":
+			if dir == "." {
+				return importPaths(pkgs, func(Package) bool { return true })
+			}
+		case "go.mod", "go.sum":
+			for _, p := range pkgs {
+				if p.Module == dir {
+					affected[p.ImportPath] = true
+				}
+			}
+			continue
+		}
+
+		for _, importPath := range embeddedBy[file] {
+			affected[importPath] = true
+		}
+
+		if p, ok := nearestPackage(byDir, modules, dir); ok {
+			affected[p.ImportPath] = true
+		}
+
+		// A deleted package is no longer listed, so it is only known by
+		// the import path of its directory.
+		if importPath, ok := dirImportPath(modules, dir); ok {
+			affected[importPath] = true
+		}
+	}
+
+	// Deps are transitive but test imports are not, so keep going until
+	// no more packages are found through the tests of other packages.
+	for changed := true; changed; {
+		changed = false
+
+		for _, p := range pkgs {
+			if affected[p.ImportPath] {
+				continue
+			}
+
+			if slices.ContainsFunc(p.Imports, func(imp string) bool { return affected[imp] }) {
+				affected[p.ImportPath] = true
+				changed = true
+			}
+		}
+	}
+
+	return importPaths(pkgs, func(p Package) bool { return affected[p.ImportPath] })
+}
+
+// nearestPackage returns the package in dir or, since files such as
+// templates or testdata are commonly kept in subdirectories, in the
+// closest parent directory within the same module.
+func nearestPackage(byDir map[string]Package, modules map[string]string, dir string) (Package, bool) {
+	for {
+		if p, ok := byDir[dir]; ok {
+			return p, true
+		}
+		if _, ok := modules[dir]; ok || dir == "." {
+			return Package{}, false
+		}
+
+		dir = path.Dir(dir)
+	}
+}
+
+// dirImportPath returns the import path of a package in dir, whether or
+// not it exists, from the closest module containing dir.
+func dirImportPath(modules map[string]string, dir string) (string, bool) {
+	var rel string
+	for {
+		if modulePath := modules[dir]; modulePath != "" {
+			return path.Join(modulePath, rel), true
+		}
+		if dir == "." {
+			return "", false
+		}
+
+		rel = path.Join(path.Base(dir), rel)
+		dir = path.Dir(dir)
+	}
+}
+
+func importPaths(pkgs []Package, include func(Package) bool) []string {
+	var paths []string
+	for _, p := range pkgs {
+		if include(p) {
+			paths = append(paths, p.ImportPath)
+		}
+	}
+
+	slices.Sort(paths)
+	return slices.Compact(paths)
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package affected
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// workspace mirrors the packages listed for go/tests/testdata/workspace/affected,
+// where app imports lib/strs and lib/strs embeds templates/suffix.txt.
+var workspace = []Package{
+	{
+		ImportPath: "example.com/app",
+		Dir:        "app",
+		Module:     "app",
+		ModulePath: "example.com/app",
+		Imports:    []string{"embed", "example.com/lib/strs", "strings", "testing"},
+	},
+	{
+		ImportPath: "example.com/lib/nums",
+		Dir:        "lib/nums",
+		Module:     "lib",
+		ModulePath: "example.com/lib",
+		Imports:    []string{"testing"},
+	},
+	{
+		ImportPath: "example.com/lib/strs",
+		Dir:        "lib/strs",
+		Module:     "lib",
+		ModulePath: "example.com/lib",
+		Imports:    []string{"embed", "strings", "testing"},
+		EmbedFiles: []string{"lib/strs/templates/suffix.txt"},
+	},
+}
+
+func TestPackages(t *testing.T) {
+	testCases := []struct {
+		name     string
+		pkgs     []Package
+		files    []string
+		expected []string
+	}{
+		{
+			name:     "unrelated file",
+			pkgs:     workspace,
+			files:    []string{"README.md"},
+			expected: nil,
+		},
+		{
+			name:     "changed package and dependents",
+			pkgs:     workspace,
+			files:    []string{"lib/strs/strs.go"},
+			expected: []string{"example.com/app", "example.com/lib/strs"},
+		},
+		{
+			name:     "embedded file",
+			pkgs:     workspace,
+			files:    []string{"lib/strs/templates/suffix.txt"},
+			expected: []string{"example.com/app", "example.com/lib/strs"},
+		},
+		{
+			name:     "nested file",
+			pkgs:     workspace,
+			files:    []string{"lib/nums/docs/usage.md"},
+			expected: []string{"example.com/lib/nums"},
+		},
+		{
+			name:     "go.mod",
+			pkgs:     workspace,
+			files:    []string{"lib/go.mod"},
+			expected: []string{"example.com/app", "example.com/lib/nums", "example.com/lib/strs"},
+		},
+		{
+			name:     "go.work",
+			pkgs:     workspace,
+			files:    []string{"go.work"},
+			expected: []string{"example.com/app", "example.com/lib/nums", "example.com/lib/strs"},
+		},
+		{
+			name:     "deleted package",
+			pkgs:     slices.DeleteFunc(slices.Clone(workspace), func(p Package) bool { return p.Dir == "lib/strs" }),
+			files:    []string{"lib/strs/strs.go", "lib/strs/strs_test.go"},
+			expected: []string{"example.com/app"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := Packages(tc.pkgs, tc.files)
+			if !slices.Equal(actual, tc.expected) {
+				t.Errorf("expected %v but received: %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseList(t *testing.T) {
+	out := `{
+	"Dir": "/src/lib/strs",
+	"ImportPath": "example.com/lib/strs",
+	"Module": {"Path": "example.com/lib"},
+	"Deps": ["strings", "embed"],
+	"TestImports": ["testing"],
+	"EmbedFiles": ["templates/suffix.txt"]
+}
+{
+	"Dir": "/src/lib",
+	"ImportPath": "example.com/lib"
+}
+`
+
+	pkgs, err := ParseList(strings.NewReader(out), "/src", "lib")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkgs) != 2 {
+		t.Fatalf("expected 2 packages but received: %d", len(pkgs))
+	}
+
+	strs := pkgs[0]
+	if strs.Dir != "lib/strs" || strs.Module != "lib" || strs.ModulePath != "example.com/lib" {
+		t.Errorf("unexpected package location: %+v", strs)
+	}
+	if !slices.Equal(strs.Imports, []string{"embed", "strings", "testing"}) {
+		t.Errorf("expected sorted imports but received: %v", strs.Imports)
+	}
+	if !slices.Equal(strs.EmbedFiles, []string{"lib/strs/templates/suffix.txt"}) {
+		t.Errorf("expected embed files relative to the root but received: %v", strs.EmbedFiles)
+	}
+
+	if pkgs[1].ModulePath != "" {
+		t.Errorf("expected no module path without a module: %q", pkgs[1].ModulePath)
+	}
+}
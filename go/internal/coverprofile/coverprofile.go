@@ -0,0 +1,410 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// Package coverprofile parses Go coverage profiles and summarizes them
+// by file and package.
+package coverprofile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Block is a single basic block from a coverage profile.
+type Block struct {
+	StartLine int
+	StartCol  int
+	EndLine   int
+	EndCol    int
+	NumStmt   int
+	Count     int
+}
+
+// Profile is the coverage of a single source file.
+type Profile struct {
+	FileName string
+	Mode     string
+	Blocks   []Block
+}
+
+// Parse reads a coverage profile as produced by go test -coverprofile.
+// Profiles are returned sorted by file name and duplicate blocks, e.g.
+// from packages tested by multiple test binaries, are merged.
+func Parse(r io.Reader) ([]*Profile, error) {
+	var mode string
+	files := make(map[string]*Profile)
+	blocks := make(map[string]map[Block]int)
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if m, ok := strings.CutPrefix(line, "mode: "); ok {
+			if mode != "" && mode != m {
+				return nil, fmt.Errorf("line %d: inconsistent coverage mode: %s", lineNum, m)
+			}
+			mode = m
+			continue
+		}
+		if mode == "" {
+			return nil, fmt.Errorf("line %d: missing mode line", lineNum)
+		}
+
+		fileName, b, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+
+		p, ok := files[fileName]
+		if !ok {
+			p = &Profile{
+				FileName: fileName,
+				Mode:     mode,
+			}
+			files[fileName] = p
+			blocks[fileName] = make(map[Block]int)
+		}
+
+		count := b.Count
+		b.Count = 0
+
+		idx, seen := blocks[fileName][b]
+		if !seen {
+			blocks[fileName][b] = len(p.Blocks)
+			b.Count = count
+			p.Blocks = append(p.Blocks, b)
+			continue
+		}
+
+		if mode == "set" {
+			p.Blocks[idx].Count = max(p.Blocks[idx].Count, count)
+		} else {
+			p.Blocks[idx].Count += count
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	profiles := make([]*Profile, 0, len(files))
+	for _, p := range files {
+		slices.SortFunc(p.Blocks, func(a, b Block) int {
+			if a.StartLine != b.StartLine {
+				return a.StartLine - b.StartLine
+			}
+			return a.StartCol - b.StartCol
+		})
+		profiles = append(profiles, p)
+	}
+	slices.SortFunc(profiles, func(a, b *Profile) int {
+		return strings.Compare(a.FileName, b.FileName)
+	})
+
+	return profiles, nil
+}
+
+// parseLine parses a line of the form:
+//
+//	name.go:line.column,line.column numberOfStatements count
+func parseLine(line string) (string, Block, error) {
+	var b Block
+
+	colon := strings.LastIndex(line, ":")
+	if colon < 0 {
+		return "", b, errors.New("malformed block: " + line)
+	}
+
+	fileName := line[:colon]
+	fields := strings.Fields(line[colon+1:])
+	if len(fields) != 3 {
+		return "", b, errors.New("malformed block: " + line)
+	}
+
+	start, end, ok := strings.Cut(fields[0], ",")
+	if !ok {
+		return "", b, errors.New("malformed block position: " + fields[0])
+	}
+
+	var err error
+	b.StartLine, b.StartCol, err = parsePosition(start)
+	if err != nil {
+		return "", b, err
+	}
+
+	b.EndLine, b.EndCol, err = parsePosition(end)
+	if err != nil {
+		return "", b, err
+	}
+
+	b.NumStmt, err = strconv.Atoi(fields[1])
+	if err != nil {
+		return "", b, err
+	}
+
+	b.Count, err = strconv.Atoi(fields[2])
+	if err != nil {
+		return "", b, err
+	}
+
+	return fileName, b, nil
+}
+
+func parsePosition(s string) (int, int, error) {
+	l, c, ok := strings.Cut(s, ".")
+	if !ok {
+		return 0, 0, errors.New("malformed position: " + s)
+	}
+
+	line, err := strconv.Atoi(l)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	col, err := strconv.Atoi(c)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return line, col, nil
+}
+
+// Stats counts covered statements.
+type Stats struct {
+	Statements int
+	Covered    int
+}
+
+// Percent returns the percentage of covered statements. A set of
+// statements with nothing to cover is considered fully covered.
+func (s Stats) Percent() float64 {
+	if s.Statements == 0 {
+		return 100
+	}
+	return float64(s.Covered) / float64(s.Statements) * 100
+}
+
+func (s *Stats) add(other Stats) {
+	s.Statements += other.Statements
+	s.Covered += other.Covered
+}
+
+// Stats counts the covered statements within the profile.
+func (p *Profile) Stats() Stats {
+	var s Stats
+	for _, b := range p.Blocks {
+		s.Statements += b.NumStmt
+		if b.Count > 0 {
+			s.Covered += b.NumStmt
+		}
+	}
+	return s
+}
+
+// Package returns the import path of the package containing the file.
+func (p *Profile) Package() string {
+	return path.Dir(p.FileName)
+}
+
+// PackageStats is the coverage of a single package.
+type PackageStats struct {
+	Name string
+	Stats
+}
+
+// ByPackage aggregates profiles by package, sorted by import path.
+func ByPackage(profiles []*Profile) []PackageStats {
+	var pkgs []PackageStats
+	idx := make(map[string]int)
+	for _, p := range profiles {
+		name := p.Package()
+
+		i, ok := idx[name]
+		if !ok {
+			i = len(pkgs)
+			idx[name] = i
+			pkgs = append(pkgs, PackageStats{Name: name})
+		}
+
+		pkgs[i].add(p.Stats())
+	}
+
+	slices.SortFunc(pkgs, func(a, b PackageStats) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return pkgs
+}
+
+// Total aggregates every profile.
+func Total(profiles []*Profile) Stats {
+	var s Stats
+	for _, p := range profiles {
+		s.add(p.Stats())
+	}
+	return s
+}
+
+type coberturaCoverage struct {
+	XMLName         xml.Name            `xml:"coverage"`
+	LineRate        string              `xml:"line-rate,attr"`
+	BranchRate      string              `xml:"branch-rate,attr"`
+	LinesCovered    int                 `xml:"lines-covered,attr"`
+	LinesValid      int                 `xml:"lines-valid,attr"`
+	BranchesCovered int                 `xml:"branches-covered,attr"`
+	BranchesValid   int                 `xml:"branches-valid,attr"`
+	Complexity      string              `xml:"complexity,attr"`
+	Timestamp       int64               `xml:"timestamp,attr"`
+	Sources         []string            `xml:"sources>source"`
+	Packages        []*coberturaPackage `xml:"packages>package"`
+}
+
+type coberturaPackage struct {
+	Name       string            `xml:"name,attr"`
+	LineRate   string            `xml:"line-rate,attr"`
+	BranchRate string            `xml:"branch-rate,attr"`
+	Complexity string            `xml:"complexity,attr"`
+	Classes    []*coberturaClass `xml:"classes>class"`
+}
+
+type coberturaClass struct {
+	Name       string           `xml:"name,attr"`
+	Filename   string           `xml:"filename,attr"`
+	LineRate   string           `xml:"line-rate,attr"`
+	BranchRate string           `xml:"branch-rate,attr"`
+	Complexity string           `xml:"complexity,attr"`
+	Methods    struct{}         `xml:"methods"`
+	Lines      []*coberturaLine `xml:"lines>line"`
+}
+
+type coberturaLine struct {
+	Number int `xml:"number,attr"`
+	Hits   int `xml:"hits,attr"`
+}
+
+// Cobertura encodes the profiles as a Cobertura XML report. File names are
+// made relative to the root of the module, whose directory is recorded as the
+// source so consumers can resolve file names to the checkout.
+func Cobertura(profiles []*Profile, module string, source string, timestamp time.Time) ([]byte, error) {
+	report := &coberturaCoverage{
+		BranchRate: rate(0, 0),
+		Complexity: "0",
+		Timestamp:  timestamp.UnixMilli(),
+		Sources:    []string{source},
+	}
+
+	pkgs := make(map[string]*coberturaPackage)
+	pkgLines := make(map[string]*Stats)
+	for _, p := range profiles {
+		name := p.Package()
+
+		pkg, ok := pkgs[name]
+		if !ok {
+			pkg = &coberturaPackage{
+				Name:       name,
+				BranchRate: rate(0, 0),
+				Complexity: "0",
+			}
+			pkgs[name] = pkg
+			pkgLines[name] = &Stats{}
+			report.Packages = append(report.Packages, pkg)
+		}
+
+		lines := lineHits(p.Blocks)
+
+		var covered int
+		for _, l := range lines {
+			if l.Hits > 0 {
+				covered++
+			}
+		}
+
+		pkg.Classes = append(pkg.Classes, &coberturaClass{
+			Name:       strings.TrimSuffix(path.Base(p.FileName), ".go"),
+			Filename:   strings.TrimPrefix(p.FileName, module+"/"),
+			LineRate:   rate(covered, len(lines)),
+			BranchRate: rate(0, 0),
+			Complexity: "0",
+			Lines:      lines,
+		})
+
+		pkgLines[name].add(Stats{Statements: len(lines), Covered: covered})
+		report.LinesValid += len(lines)
+		report.LinesCovered += covered
+	}
+
+	for _, pkg := range report.Packages {
+		s := pkgLines[pkg.Name]
+		pkg.LineRate = rate(s.Covered, s.Statements)
+	}
+	report.LineRate = rate(report.LinesCovered, report.LinesValid)
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">` + "\n")
+
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+
+	err := enc.Encode(report)
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n")
+
+	return buf.Bytes(), nil
+}
+
+// lineHits flattens blocks into per-line hit counts. A line spanned by
+// multiple blocks reports the highest count of any of them.
+func lineHits(blocks []Block) []*coberturaLine {
+	hits := make(map[int]int)
+	for _, b := range blocks {
+		if b.NumStmt == 0 {
+			continue
+		}
+
+		for n := b.StartLine; n <= b.EndLine; n++ {
+			count, ok := hits[n]
+			if !ok || b.Count > count {
+				hits[n] = b.Count
+			}
+		}
+	}
+
+	lines := make([]*coberturaLine, 0, len(hits))
+	for n, count := range hits {
+		lines = append(lines, &coberturaLine{
+			Number: n,
+			Hits:   count,
+		})
+	}
+	slices.SortFunc(lines, func(a, b *coberturaLine) int {
+		return a.Number - b.Number
+	})
+
+	return lines
+}
+
+func rate(covered, total int) string {
+	if total == 0 {
+		return "0"
+	}
+	return strconv.FormatFloat(float64(covered)/float64(total), 'f', 4, 64)
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// Package govulncheck parses the JSON output of govulncheck.
+package govulncheck
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Level is how precisely a vulnerability was found in the code.
+type Level int
+
+const (
+	// The vulnerable module is required.
+	Module Level = iota
+
+	// A vulnerable package is imported.
+	Package
+
+	// A vulnerable function is reachable from the code.
+	Symbol
+)
+
+// Finding is the most precise finding of a single vulnerability.
+type Finding struct {
+	ID           string
+	Summary      string
+	FixedVersion string
+
+	// Module, package and function of the first frame of the trace,
+	// i.e. the vulnerable code, as far as they are known.
+	Module   string
+	Version  string
+	Package  string
+	Function string
+
+	Level Level
+}
+
+type message struct {
+	OSV *struct {
+		ID      string `json:"id"`
+		Summary string `json:"summary"`
+	} `json:"osv"`
+
+	Finding *struct {
+		OSV          string `json:"osv"`
+		FixedVersion string `json:"fixed_version"`
+		Trace        []struct {
+			Module   string `json:"module"`
+			Version  string `json:"version"`
+			Package  string `json:"package"`
+			Function string `json:"function"`
+			Receiver string `json:"receiver"`
+		} `json:"trace"`
+	} `json:"finding"`
+}
+
+// Parse reads the stream of messages written by govulncheck -format json
+// and returns a finding per vulnerability, sorted by ID.
+func Parse(r io.Reader) ([]Finding, error) {
+	dec := json.NewDecoder(r)
+
+	summaries := make(map[string]string)
+	findings := make(map[string]Finding)
+	for {
+		var msg message
+		err := dec.Decode(&msg)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if msg.OSV != nil {
+			summaries[msg.OSV.ID] = msg.OSV.Summary
+		}
+		if msg.Finding == nil || len(msg.Finding.Trace) == 0 {
+			continue
+		}
+
+		frame := msg.Finding.Trace[0]
+
+		f := Finding{
+			ID:           msg.Finding.OSV,
+			FixedVersion: msg.Finding.FixedVersion,
+			Module:       frame.Module,
+			Version:      frame.Version,
+			Package:      frame.Package,
+			Function:     frame.Function,
+		}
+		if frame.Receiver != "" {
+			f.Function = strings.TrimPrefix(frame.Receiver, "*") + "." + frame.Function
+		}
+
+		switch {
+		case frame.Function != "":
+			f.Level = Symbol
+		case frame.Package != "":
+			f.Level = Package
+		default:
+			f.Level = Module
+		}
+
+		// govulncheck reports a vulnerability at every level it reaches
+		// and once per trace, so only the first most precise one is kept.
+		existing, ok := findings[f.ID]
+		if ok && existing.Level >= f.Level {
+			continue
+		}
+		findings[f.ID] = f
+	}
+
+	result := make([]Finding, 0, len(findings))
+	for _, f := range findings {
+		f.Summary = summaries[f.ID]
+		result = append(result, f)
+	}
+
+	slices.SortFunc(result, func(a, b Finding) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return result, nil
+}
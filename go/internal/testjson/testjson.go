@@ -0,0 +1,326 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// Package testjson parses the event stream produced by go test -json
+// and converts it to a JUnit XML report.
+package testjson
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Actions reported by test2json.
+const (
+	ActionStart       = "start"
+	ActionRun         = "run"
+	ActionPause       = "pause"
+	ActionCont        = "cont"
+	ActionPass        = "pass"
+	ActionBench       = "bench"
+	ActionFail        = "fail"
+	ActionOutput      = "output"
+	ActionSkip        = "skip"
+	ActionBuildOutput = "build-output"
+	ActionBuildFail   = "build-fail"
+)
+
+// Event mirrors the JSON object emitted by test2json for every line of test output.
+type Event struct {
+	Time        time.Time `json:"Time"`
+	Action      string    `json:"Action"`
+	Package     string    `json:"Package"`
+	ImportPath  string    `json:"ImportPath"`
+	Test        string    `json:"Test"`
+	Elapsed     float64   `json:"Elapsed"`
+	Output      string    `json:"Output"`
+	FailedBuild string    `json:"FailedBuild"`
+}
+
+// Test is the outcome of a single test or subtest.
+type Test struct {
+	Package string
+	Name    string
+	Result  string
+	Elapsed float64
+	Output  string
+}
+
+// Package is the outcome of all tests within a single package.
+type Package struct {
+	Name    string
+	Result  string
+	Elapsed float64
+	Output  string
+	Tests   []*Test
+}
+
+// Report is the outcome of a go test -json invocation.
+type Report struct {
+	Packages []*Package
+}
+
+// Tests returns every test in the report in the order they were started.
+func (r *Report) Tests() []*Test {
+	var tests []*Test
+	for _, pkg := range r.Packages {
+		tests = append(tests, pkg.Tests...)
+	}
+	return tests
+}
+
+type packageState struct {
+	pkg    *Package
+	output strings.Builder
+	tests  map[string]*testState
+}
+
+type testState struct {
+	test   *Test
+	output strings.Builder
+}
+
+// Parse reads a go test -json event stream. Lines which are not
+// JSON objects, e.g. build errors written by older toolchains, are ignored.
+func Parse(r io.Reader) (*Report, error) {
+	var order []string
+	states := make(map[string]*packageState)
+
+	getPackage := func(name string) *packageState {
+		state, ok := states[name]
+		if ok {
+			return state
+		}
+
+		state = &packageState{
+			pkg:   &Package{Name: name},
+			tests: make(map[string]*testState),
+		}
+		states[name] = state
+		order = append(order, name)
+		return state
+	}
+
+	// Build output is reported against the import path being built
+	// so it must be attributed to the package whose build failed.
+	buildOutput := make(map[string]*strings.Builder)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+
+		var ev Event
+		err := json.Unmarshal(line, &ev)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode test event: %w", err)
+		}
+
+		switch ev.Action {
+		case ActionBuildOutput:
+			b, ok := buildOutput[ev.ImportPath]
+			if !ok {
+				b = new(strings.Builder)
+				buildOutput[ev.ImportPath] = b
+			}
+			b.WriteString(ev.Output)
+			continue
+		case ActionBuildFail:
+			continue
+		}
+
+		if ev.Package == "" {
+			continue
+		}
+
+		state := getPackage(ev.Package)
+		if ev.FailedBuild != "" {
+			if b, ok := buildOutput[ev.FailedBuild]; ok {
+				state.output.WriteString(b.String())
+			}
+		}
+
+		if ev.Test == "" {
+			switch ev.Action {
+			case ActionOutput:
+				state.output.WriteString(ev.Output)
+			case ActionPass, ActionFail, ActionSkip:
+				state.pkg.Result = ev.Action
+				state.pkg.Elapsed = ev.Elapsed
+			}
+			continue
+		}
+
+		ts, ok := state.tests[ev.Test]
+		if !ok {
+			ts = &testState{
+				test: &Test{
+					Package: ev.Package,
+					Name:    ev.Test,
+				},
+			}
+			state.tests[ev.Test] = ts
+			state.pkg.Tests = append(state.pkg.Tests, ts.test)
+		}
+
+		switch ev.Action {
+		case ActionOutput:
+			ts.output.WriteString(ev.Output)
+		case ActionPass, ActionFail, ActionSkip:
+			ts.test.Result = ev.Action
+			ts.test.Elapsed = ev.Elapsed
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Packages: make([]*Package, 0, len(order)),
+	}
+	for _, name := range order {
+		state := states[name]
+		state.pkg.Output = state.output.String()
+
+		for _, ts := range state.tests {
+			ts.test.Output = ts.output.String()
+
+			// A test without an outcome was interrupted, e.g. by a panic or timeout.
+			if ts.test.Result == "" {
+				ts.test.Result = ActionFail
+			}
+		}
+
+		report.Packages = append(report.Packages, state.pkg)
+	}
+
+	return report, nil
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Skipped  int               `xml:"skipped,attr"`
+	Time     string            `xml:"time,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	Skipped   int              `xml:"skipped,attr"`
+	Time      string           `xml:"time,attr"`
+	TestCases []*junitTestCase `xml:"testcase"`
+	SystemOut string           `xml:"system-out,omitempty"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message  string `xml:"message,attr"`
+	Contents string `xml:",chardata"`
+}
+
+// JUnit encodes the report as JUnit XML.
+func JUnit(report *Report) ([]byte, error) {
+	suites := &junitTestSuites{}
+
+	var elapsed float64
+	for _, pkg := range report.Packages {
+		suite := &junitTestSuite{
+			Name: pkg.Name,
+			Time: formatSeconds(pkg.Elapsed),
+		}
+		elapsed += pkg.Elapsed
+
+		for _, test := range pkg.Tests {
+			tc := &junitTestCase{
+				ClassName: pkg.Name,
+				Name:      test.Name,
+				Time:      formatSeconds(test.Elapsed),
+			}
+
+			switch test.Result {
+			case ActionFail:
+				suite.Failures++
+				tc.Failure = &junitMessage{
+					Message:  "Failed",
+					Contents: test.Output,
+				}
+			case ActionSkip:
+				suite.Skipped++
+				tc.Skipped = &junitMessage{
+					Message:  "Skipped",
+					Contents: test.Output,
+				}
+			default:
+				tc.SystemOut = test.Output
+			}
+
+			suite.Tests++
+			suite.TestCases = append(suite.TestCases, tc)
+		}
+
+		// A package can fail without any failing test e.g. due to a build
+		// failure or TestMain exiting early so it is reported as its own case.
+		if pkg.Result == ActionFail && suite.Failures == 0 {
+			suite.Tests++
+			suite.Failures++
+			suite.TestCases = append(suite.TestCases, &junitTestCase{
+				ClassName: pkg.Name,
+				Name:      "[package failed]",
+				Time:      formatSeconds(pkg.Elapsed),
+				Failure: &junitMessage{
+					Message:  "Failed",
+					Contents: pkg.Output,
+				},
+			})
+		} else {
+			suite.SystemOut = pkg.Output
+		}
+
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+		suites.Suites = append(suites.Suites, suite)
+	}
+	suites.Time = formatSeconds(elapsed)
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+
+	err := enc.Encode(suites)
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n")
+
+	return buf.Bytes(), nil
+}
+
+func formatSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
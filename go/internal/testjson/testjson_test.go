@@ -0,0 +1,241 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package testjson
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+const passFailSkip = `{"Action":"start","Package":"example.com/add"}
+{"Action":"run","Package":"example.com/add","Test":"TestAdd"}
+{"Action":"output","Package":"example.com/add","Test":"TestAdd","Output":"=== RUN   TestAdd\n"}
+{"Action":"output","Package":"example.com/add","Test":"TestAdd","Output":"--- PASS: TestAdd (0.00s)\n"}
+{"Action":"pass","Package":"example.com/add","Test":"TestAdd","Elapsed":0.01}
+{"Action":"run","Package":"example.com/add","Test":"TestSub"}
+{"Action":"output","Package":"example.com/add","Test":"TestSub","Output":"    add_test.go:12: expected 1 but received: 2\n"}
+{"Action":"fail","Package":"example.com/add","Test":"TestSub","Elapsed":0.02}
+{"Action":"run","Package":"example.com/add","Test":"TestMul"}
+{"Action":"skip","Package":"example.com/add","Test":"TestMul","Elapsed":0}
+{"Action":"output","Package":"example.com/add","Output":"FAIL\n"}
+{"Action":"fail","Package":"example.com/add","Elapsed":0.5}
+`
+
+const buildFailure = `{"ImportPath":"example.com/broken [example.com/broken.test]","Action":"build-output","Output":"# example.com/broken\n"}
+{"ImportPath":"example.com/broken [example.com/broken.test]","Action":"build-output","Output":"./broken.go:3:1: syntax error\n"}
+{"ImportPath":"example.com/broken [example.com/broken.test]","Action":"build-fail"}
+{"Action":"start","Package":"example.com/broken"}
+{"Action":"output","Package":"example.com/broken","Output":"FAIL\texample.com/broken [build failed]\n"}
+{"Action":"fail","Package":"example.com/broken","Elapsed":0,"FailedBuild":"example.com/broken [example.com/broken.test]"}
+`
+
+const interrupted = `{"Action":"run","Package":"example.com/panic","Test":"TestPanic"}
+{"Action":"output","Package":"example.com/panic","Test":"TestPanic","Output":"panic: boom\n"}
+{"Action":"fail","Package":"example.com/panic","Elapsed":0.1}
+`
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		packages []Package
+		tests    []Test
+	}{
+		{
+			name:  "pass fail and skip",
+			input: passFailSkip,
+			packages: []Package{
+				{Name: "example.com/add", Result: ActionFail, Elapsed: 0.5, Output: "FAIL\n"},
+			},
+			tests: []Test{
+				{
+					Package: "example.com/add",
+					Name:    "TestAdd",
+					Result:  ActionPass,
+					Elapsed: 0.01,
+					Output:  "=== RUN   TestAdd\n--- PASS: TestAdd (0.00s)\n",
+				},
+				{
+					Package: "example.com/add",
+					Name:    "TestSub",
+					Result:  ActionFail,
+					Elapsed: 0.02,
+					Output:  "    add_test.go:12: expected 1 but received: 2\n",
+				},
+				{
+					Package: "example.com/add",
+					Name:    "TestMul",
+					Result:  ActionSkip,
+				},
+			},
+		},
+		{
+			name:  "build failure",
+			input: buildFailure,
+			packages: []Package{
+				{
+					Name:   "example.com/broken",
+					Result: ActionFail,
+					Output: "FAIL\texample.com/broken [build failed]\n# example.com/broken\n./broken.go:3:1: syntax error\n",
+				},
+			},
+		},
+		{
+			name:  "interrupted test",
+			input: interrupted,
+			packages: []Package{
+				{Name: "example.com/panic", Result: ActionFail, Elapsed: 0.1},
+			},
+			tests: []Test{
+				{
+					Package: "example.com/panic",
+					Name:    "TestPanic",
+					Result:  ActionFail,
+					Output:  "panic: boom\n",
+				},
+			},
+		},
+		{
+			name:  "non json lines",
+			input: "# example.com/old\nbuild failed\n\n" + interrupted,
+			packages: []Package{
+				{Name: "example.com/panic", Result: ActionFail, Elapsed: 0.1},
+			},
+			tests: []Test{
+				{
+					Package: "example.com/panic",
+					Name:    "TestPanic",
+					Result:  ActionFail,
+					Output:  "panic: boom\n",
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := Parse(strings.NewReader(tc.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(report.Packages) != len(tc.packages) {
+				t.Fatalf("expected %d packages but received: %d", len(tc.packages), len(report.Packages))
+			}
+			for i, expected := range tc.packages {
+				actual := report.Packages[i]
+				if actual.Name != expected.Name ||
+					actual.Result != expected.Result ||
+					actual.Elapsed != expected.Elapsed ||
+					actual.Output != expected.Output {
+					t.Errorf("expected package %+v but received: %+v", expected, *actual)
+				}
+			}
+
+			tests := report.Tests()
+			if len(tests) != len(tc.tests) {
+				t.Fatalf("expected %d tests but received: %d", len(tc.tests), len(tests))
+			}
+			for i, expected := range tc.tests {
+				if *tests[i] != expected {
+					t.Errorf("expected test %+v but received: %+v", expected, *tests[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseInvalidEvent(t *testing.T) {
+	_, err := Parse(strings.NewReader(`{"Action":`))
+	if err == nil {
+		t.Error("expected an error for a truncated event")
+	}
+}
+
+func TestJUnit(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		tests    int
+		failures int
+		skipped  int
+		cases    map[string]string
+	}{
+		{
+			name:     "pass fail and skip",
+			input:    passFailSkip,
+			tests:    3,
+			failures: 1,
+			skipped:  1,
+			cases: map[string]string{
+				"TestAdd": "",
+				"TestSub": "failure",
+				"TestMul": "skipped",
+			},
+		},
+		{
+			name:     "build failure",
+			input:    buildFailure,
+			tests:    1,
+			failures: 1,
+			cases: map[string]string{
+				"[package failed]": "failure",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := Parse(strings.NewReader(tc.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := JUnit(report)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var suites junitTestSuites
+			err = xml.Unmarshal(b, &suites)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if suites.Tests != tc.tests || suites.Failures != tc.failures || suites.Skipped != tc.skipped {
+				t.Errorf(
+					"expected %d tests, %d failures and %d skipped but received: %d, %d and %d",
+					tc.tests, tc.failures, tc.skipped,
+					suites.Tests, suites.Failures, suites.Skipped,
+				)
+			}
+
+			actual := make(map[string]string)
+			for _, suite := range suites.Suites {
+				for _, c := range suite.TestCases {
+					var outcome string
+					switch {
+					case c.Failure != nil:
+						outcome = "failure"
+					case c.Skipped != nil:
+						outcome = "skipped"
+					}
+					actual[c.Name] = outcome
+				}
+			}
+			if len(actual) != len(tc.cases) {
+				t.Errorf("expected test cases %v but received: %v", tc.cases, actual)
+			}
+			for name, expected := range tc.cases {
+				outcome, ok := actual[name]
+				if !ok || outcome != expected {
+					t.Errorf("expected %s to be %q but received: %q", name, expected, outcome)
+				}
+			}
+		})
+	}
+}
@@ -8,6 +8,8 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 
 	"dagger/go/internal/dagger"
 )
@@ -16,6 +18,62 @@ type Linter interface {
 	DaggerObject
 
 	Lint(ctr *dagger.Container) *dagger.File
+
+	// Parse a report returned by Lint into individual findings.
+	Findings(
+		ctx context.Context,
+		ctr *dagger.Container,
+		report *dagger.File,
+	) ([]LintFinding, error)
+}
+
+type LintFinding interface {
+	DaggerObject
+
+	// Path of the file containing the finding.
+	File(ctx context.Context) (string, error)
+
+	// Line number of the finding within the file.
+	Line(ctx context.Context) (int, error)
+
+	// Name of the rule or linter which reported the finding.
+	Rule(ctx context.Context) (string, error)
+
+	// Severity of the finding, e.g. info, warning or error.
+	Severity(ctx context.Context) (string, error)
+
+	// Description of the finding.
+	Message(ctx context.Context) (string, error)
+}
+
+type LintSeverity string
+
+const (
+	Info    LintSeverity = "info"
+	Warning LintSeverity = "warning"
+	Error   LintSeverity = "error"
+)
+
+// rank orders severities so findings can be compared against a threshold.
+// Unrecognized severities, including none at all, are treated as errors
+// since most linters only report problems which should be fixed.
+func (s LintSeverity) rank() int {
+	switch LintSeverity(strings.ToLower(string(s))) {
+	case Info, "note", "low", "hint":
+		return 0
+	case Warning, "warn", "medium":
+		return 1
+	default:
+		return 2
+	}
+}
+
+type DependencyScanner interface {
+	DaggerObject
+
+	// Scan a directory containing a Go module and return a report,
+	// failing if the module depends on vulnerable modules.
+	ScanDirectory(dir *dagger.Directory) *dagger.File
 }
 
 type StaticAnalyzer interface {
@@ -38,6 +96,42 @@ type Library struct {
 
 	// +private
 	StaticAnalyzer StaticAnalyzer
+
+	// +private
+	DependencyScanner DependencyScanner
+
+	// +private
+	SbomGenerator SbomGenerator
+
+	// +private
+	CoverageThreshold float64
+
+	// +private
+	PackageCoverageThreshold float64
+
+	// +private
+	LintSeverity LintSeverity
+
+	// +private
+	VulncheckEnabled bool
+
+	// +private
+	VulnDb *dagger.Directory
+
+	// +private
+	VulncheckVersion string
+
+	// +private
+	VulncheckBinary *dagger.File
+
+	// +private
+	Formatter Formatter
+
+	// +private
+	LocalPrefix string
+
+	// +private
+	FormatterVersion string
 }
 
 // A set of functions for working with a library written in Go.
@@ -47,24 +141,114 @@ func (m *Mod) Library(
 
 	// +optional
 	staticAnalyzer StaticAnalyzer,
+
+	// Specify a tool for scanning module dependencies for known vulnerabilities,
+	// e.g. trivy. Dependencies are only scanned when a scanner is given.
+	// +optional
+	dependencyScanner DependencyScanner,
+
+	// Specify a tool for generating SBOMs of the module and application containers.
+	// +optional
+	sbomGenerator SbomGenerator,
 ) *Library {
 	if linter == nil {
-		linter = dag.Noop().GoLinter()
+		linter = noopLinter{dag.Noop().GoLinter()}
 	}
 	if staticAnalyzer == nil {
 		staticAnalyzer = dag.Noop().GoStaticAnalyzer()
 	}
+	if sbomGenerator == nil {
+		sbomGenerator = dag.Trivy()
+	}
 
 	return &Library{
-		Module:         m,
-		Linter:         linter,
-		StaticAnalyzer: staticAnalyzer,
+		Module:            m,
+		Linter:            linter,
+		StaticAnalyzer:    staticAnalyzer,
+		DependencyScanner: dependencyScanner,
+		SbomGenerator:     sbomGenerator,
 	}
 }
 
-// Run all continuous integration functions.
+// Fail Ci when test coverage is below these minimum percentages.
+func (lib *Library) WithCoverageThreshold(
+	// Minimum percentage of statements covered across all packages.
+	// +optional
+	total float64,
+
+	// Minimum percentage of statements covered within each package.
+	// +optional
+	perPackage float64,
+) *Library {
+	lib.CoverageThreshold = total
+	lib.PackageCoverageThreshold = perPackage
+	return lib
+}
+
+// Fail Ci if any lint finding is at or above this severity.
+func (lib *Library) WithLintSeverity(severity LintSeverity) *Library {
+	lib.LintSeverity = severity
+	return lib
+}
+
+// Check for reachable vulnerabilities with govulncheck in Ci.
+func (lib *Library) WithVulncheck(
+	// A local copy of the Go vulnerability database.
+	// +optional
+	db *dagger.Directory,
+
+	// Version of govulncheck to install, defaults to a pinned version.
+	// +optional
+	version string,
+
+	// A prebuilt govulncheck binary used instead of installing govulncheck.
+	// +optional
+	binary *dagger.File,
+) *Library {
+	lib.VulncheckEnabled = true
+	lib.VulnDb = db
+	lib.VulncheckVersion = version
+	lib.VulncheckBinary = binary
+	return lib
+}
+
+// Validate source code is formatted in Ci.
+func (lib *Library) WithFormat(
+	// +default="gofmt"
+	formatter Formatter,
+
+	// Import prefix of local packages, only supported by goimports.
+	// +optional
+	localPrefix string,
+
+	// Version of goimports or gofumpt to install, defaults to a pinned
+	// version of the formatter.
+	// +optional
+	version string,
+) *Library {
+	lib.Formatter = formatter
+	lib.LocalPrefix = localPrefix
+	lib.FormatterVersion = version
+	return lib
+}
+
+// Run all continuous integration functions, along with the checks
+// enabled by WithCoverageThreshold, WithLintSeverity, WithVulncheck and
+// WithFormat.
 func (lib *Library) Ci(ctx context.Context) error {
-	err := lib.Generate(ctx, "./...")
+	_, err := lib.ScanDependencies(ctx)
+	if err != nil {
+		return err
+	}
+
+	if lib.VulncheckEnabled {
+		err = lib.Vulncheck(ctx, lib.VulnDb, lib.VulncheckVersion, lib.VulncheckBinary)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = lib.Generate(ctx, "./...")
 	if err != nil {
 		return err
 	}
@@ -74,9 +258,30 @@ func (lib *Library) Ci(ctx context.Context) error {
 		return err
 	}
 
+	if lib.Formatter != "" {
+		err = lib.Format(ctx, lib.Formatter, lib.LocalPrefix, lib.FormatterVersion)
+		if err != nil {
+			return err
+		}
+	}
+
 	lintReport := lib.Lint(ctx)
+	if lib.LintSeverity != "" {
+		err = lib.LintCheck(ctx, lintReport, lib.LintSeverity)
+		if err != nil {
+			return err
+		}
+	}
 
-	coverageReport := lib.Test("./...", true)
+	coverageReport, err := lib.Test(ctx, "./...", true)
+	if err != nil {
+		return err
+	}
+
+	err = lib.Module.Coverage(coverageReport).Check(ctx, lib.CoverageThreshold, lib.PackageCoverageThreshold)
+	if err != nil {
+		return err
+	}
 
 	err = lib.StaticAnalysis(ctx, lintReport, coverageReport)
 	if err != nil {
@@ -110,6 +315,59 @@ func (lib *Library) Tidy(ctx context.Context) error {
 	return nil
 }
 
+// Scan module dependencies for known vulnerabilities, returning no report
+// when no dependency scanner was given.
+func (lib *Library) ScanDependencies(ctx context.Context) (*dagger.File, error) {
+	if lib.DependencyScanner == nil {
+		return nil, nil
+	}
+
+	return lib.DependencyScanner.ScanDirectory(lib.Module.Ctr.Directory(".")).Sync(ctx)
+}
+
+// Validate source code is formatted.
+func (lib *Library) Format(
+	ctx context.Context,
+
+	// +default="gofmt"
+	formatter Formatter,
+
+	// Import prefix of local packages, only supported by goimports.
+	// +optional
+	localPrefix string,
+
+	// Version of goimports or gofumpt to install, defaults to a pinned
+	// version of the formatter.
+	// +optional
+	version string,
+) error {
+	f, err := lib.Module.Format(formatter, localPrefix, version)
+	if err != nil {
+		return err
+	}
+
+	return f.Check(ctx)
+}
+
+// Validate no known vulnerable function is reachable from the module.
+func (lib *Library) Vulncheck(
+	ctx context.Context,
+
+	// A local copy of the Go vulnerability database.
+	// +optional
+	vulnDb *dagger.Directory,
+
+	// Version of govulncheck to install, defaults to a pinned version.
+	// +optional
+	version string,
+
+	// A prebuilt govulncheck binary used instead of installing govulncheck.
+	// +optional
+	binary *dagger.File,
+) error {
+	return lib.Module.Vulncheck(vulnDb, version, binary).Check(ctx, "./...")
+}
+
 // Lint source code.
 func (lib *Library) Lint(ctx context.Context) *dagger.File {
 	if lib.Linter == nil {
@@ -119,15 +377,89 @@ func (lib *Library) Lint(ctx context.Context) *dagger.File {
 	return lib.Linter.Lint(lib.Module.Ctr)
 }
 
+// Validate no lint findings at or above the given severity.
+func (lib *Library) LintCheck(
+	ctx context.Context,
+
+	report *dagger.File,
+
+	// +default="error"
+	severity LintSeverity,
+) error {
+	if lib.Linter == nil {
+		return nil
+	}
+
+	findings, err := lib.Linter.Findings(ctx, lib.Module.Ctr, report)
+	if err != nil {
+		return err
+	}
+
+	var failures []string
+	for _, finding := range findings {
+		s, err := finding.Severity(ctx)
+		if err != nil {
+			return err
+		}
+		if LintSeverity(s).rank() < severity.rank() {
+			continue
+		}
+
+		failure, err := formatLintFinding(ctx, finding, s)
+		if err != nil {
+			return err
+		}
+
+		failures = append(failures, failure)
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf(
+			"found %d lint finding(s) at or above %s severity:\n%s",
+			len(failures),
+			severity,
+			strings.Join(failures, "\n"),
+		)
+	}
+
+	return nil
+}
+
+func formatLintFinding(ctx context.Context, finding LintFinding, severity string) (string, error) {
+	file, err := finding.File(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	line, err := finding.Line(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	rule, err := finding.Rule(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	msg, err := finding.Message(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s:%d: %s (%s, %s)", file, line, msg, rule, severity), nil
+}
+
 // Run tests and return coverage report.
 func (lib *Library) Test(
+	ctx context.Context,
+
 	// +default="./..."
 	pkg string,
 
 	// +default=true
 	race bool,
-) *dagger.File {
-	return lib.Module.Test(pkg, true).Coverage(Atomic)
+) (*dagger.File, error) {
+	return lib.Module.Test(pkg, race).Coverage(ctx, Atomic)
 }
 
 // Perform static analysis.
@@ -0,0 +1,160 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"dagger/go/internal/dagger"
+
+	"github.com/containerd/platforms"
+)
+
+// Package application binaries into release archives.
+//
+// Each platform is archived as <name>_<version>_<os>_<arch>, using zip
+// for windows and tar.gz for every other OS, and a checksums.txt with
+// the SHA-256 sum of every archive is included alongside them.
+func (app *Application) Release(
+	ctx context.Context,
+
+	// Version included in archive names, without any leading v.
+	// +optional
+	version string,
+
+	// Name of the binary. Defaults to the last element of the main package import path.
+	// +optional
+	name string,
+
+	// +default=["-s", "-w"]
+	ldflags []string,
+
+	// +optional
+	tags []string,
+
+	// +default=true
+	trimpath bool,
+
+	// +optional
+	enableCGO bool,
+
+	// +default=["linux/amd64", "linux/arm64", "darwin/amd64", "darwin/arm64", "windows/amd64"]
+	platforms []dagger.Platform,
+
+	// Additional files, e.g. LICENSE or README.md, to include in every archive.
+	// +optional
+	include []*dagger.File,
+) (*dagger.Directory, error) {
+	mod := app.Library.Module
+
+	if name == "" {
+		var err error
+		name, err = mod.binaryName(ctx, app.MainPackagePath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	binaries, err := mod.BuildMatrix(
+		ctx,
+		app.MainPackagePath,
+		name,
+		false,
+		ldflags,
+		tags,
+		trimpath,
+		enableCGO,
+		platforms,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dist := dag.Directory()
+	for _, platform := range platforms {
+		binFileName, err := platformBinaryName(name, platform)
+		if err != nil {
+			return nil, err
+		}
+
+		archiveName, isWindows, err := releaseArchiveName(name, version, platform)
+		if err != nil {
+			return nil, err
+		}
+
+		binName := name
+		if isWindows {
+			binName += ".exe"
+		}
+
+		contents := dag.Directory().
+			WithFile(binName, binaries.File(binFileName)).
+			WithFiles(".", include)
+
+		if isWindows {
+			archiveName += ".zip"
+			dist = dist.WithFile(archiveName, dag.Archive().Zip().Create(contents, dagger.ArchiveZipCreateOpts{
+				Name:         archiveName,
+				Reproducible: true,
+			}))
+			continue
+		}
+
+		archiveName += ".tar.gz"
+		dist = dist.WithFile(archiveName, dag.Archive().Tar().Create(contents, dagger.ArchiveTarCreateOpts{
+			Name:         archiveName,
+			Compression:  dagger.ArchiveCompressionGzip,
+			Reproducible: true,
+		}))
+	}
+
+	checksums, err := releaseChecksums(ctx, dist)
+	if err != nil {
+		return nil, err
+	}
+
+	return dist.WithNewFile("checksums.txt", checksums), nil
+}
+
+// releaseChecksums lists the SHA-256 sum of every file within the directory
+// in the format written by sha256sum.
+func releaseChecksums(ctx context.Context, dir *dagger.Directory) (string, error) {
+	entries, err := dir.Entries(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, entry := range entries {
+		digest, err := dir.File(entry).Digest(ctx, dagger.FileDigestOpts{
+			ExcludeMetadata: true,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(&sb, "%s  %s\n", strings.TrimPrefix(digest, "sha256:"), entry)
+	}
+
+	return sb.String(), nil
+}
+
+func releaseArchiveName(name, version string, platform dagger.Platform) (string, bool, error) {
+	p, err := platforms.Parse(string(platform))
+	if err != nil {
+		return "", false, err
+	}
+
+	parts := []string{name}
+	if version != "" {
+		parts = append(parts, strings.TrimPrefix(version, "v"))
+	}
+	parts = append(parts, p.OS, p.Architecture+p.Variant)
+
+	return strings.Join(parts, "_"), p.OS == "windows", nil
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"dagger/go/internal/dagger"
+
+	"github.com/sourcegraph/conc/pool"
+)
+
+type SbomGenerator interface {
+	DaggerObject
+
+	// Generate an SBOM for a directory containing a Go module.
+	// The format is either cyclonedx or spdx.
+	DirectorySbom(dir *dagger.Directory, format string) *dagger.File
+
+	// Generate an SBOM for a container, including the build info of Go binaries.
+	// The format is either cyclonedx or spdx.
+	ContainerSbom(ctr *dagger.Container, format string) *dagger.File
+}
+
+type SbomFormat string
+
+const (
+	Cyclonedx SbomFormat = "cyclonedx"
+	Spdx      SbomFormat = "spdx"
+)
+
+// mediaType is the artifact type of an SBOM attached to a published image.
+func (f SbomFormat) mediaType() string {
+	switch f {
+	case Spdx:
+		return "application/spdx+json"
+	default:
+		return "application/vnd.cyclonedx+json"
+	}
+}
+
+// Generate an SBOM of the module dependencies.
+func (lib *Library) Sbom(
+	// +default="cyclonedx"
+	format SbomFormat,
+) *dagger.File {
+	if lib.SbomGenerator == nil {
+		return nil
+	}
+
+	return lib.SbomGenerator.DirectorySbom(lib.Module.Ctr.Directory("."), string(format))
+}
+
+// Generate an SBOM for each application container image, in the same
+// order as the given platform variants.
+func (app *Application) Sbom(
+	ctx context.Context,
+
+	platformVariants []*dagger.Container,
+
+	// +default="cyclonedx"
+	format SbomFormat,
+) ([]*dagger.File, error) {
+	if app.Library.SbomGenerator == nil {
+		return []*dagger.File{}, nil
+	}
+
+	ep := pool.New().WithErrors().WithContext(ctx)
+
+	sboms := make([]*dagger.File, len(platformVariants))
+	for i, ctr := range platformVariants {
+		ep.Go(func(ctx context.Context) error {
+			sbom, err := app.Library.SbomGenerator.ContainerSbom(ctr, string(format)).Sync(ctx)
+			if err != nil {
+				return err
+			}
+
+			sboms[i] = sbom
+			return nil
+		})
+	}
+
+	err := ep.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	return sboms, nil
+}
+
+// attachSboms pushes an SBOM for each platform variant to the registry
+// as an OCI artifact referring to the published image index, annotated
+// with the platform it describes.
+func (app *Application) attachSboms(
+	ctx context.Context,
+	ref string,
+	registry string,
+	platformVariants []*dagger.Container,
+	registryUsername string,
+	registrySecret *dagger.Secret,
+	format SbomFormat,
+) error {
+	sboms, err := app.Sbom(ctx, platformVariants, format)
+	if err != nil {
+		return err
+	}
+
+	oras := app.withRegistry(dag.Container().From("ghcr.io/oras-project/oras:v1.2.3"), registry).
+		WithEnvVariable("ORAS_USERNAME", registryUsername).
+		WithSecretVariable("ORAS_PASSWORD", registrySecret).
+		WithWorkdir("/sbom")
+
+	for i, sbom := range sboms {
+		platform, err := platformVariants[i].Platform(ctx)
+		if err != nil {
+			return err
+		}
+
+		name := fmt.Sprintf("sbom-%d.json", i)
+
+		args := []string{
+			"sh",
+			"-c",
+			`printf '%s' "$ORAS_PASSWORD" | oras attach --username "$ORAS_USERNAME" --password-stdin "$@"`,
+			"oras",
+			"--artifact-type",
+			format.mediaType(),
+			"--annotation",
+			"org.opencontainers.image.platform=" + string(platform),
+		}
+		if app.RegistryService != nil {
+			args = append(args, "--plain-http")
+		}
+		args = append(args, ref, name+":"+format.mediaType())
+
+		_, err = oras.
+			WithMountedFile(name, sbom).
+			WithExec(args).
+			Sync(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,264 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"dagger/go/internal/dagger"
+)
+
+const cosignImage = "ghcr.io/sigstore/cosign/cosign:v2.4.1"
+
+// Sign published images with a cosign private key.
+func (app *Application) WithSigningKey(
+	key *dagger.Secret,
+
+	// Password of the private key, if it is encrypted.
+	// +optional
+	password *dagger.Secret,
+
+	// Upload signatures to the Rekor transparency log.
+	// +optional
+	tlogUpload bool,
+) *Application {
+	app.SigningKey = key
+	app.SigningPassword = password
+	app.TlogUpload = tlogUpload
+	return app
+}
+
+// Push images to a registry service, e.g. registry:2, instead of a
+// registry on the network. The service is bound under the host name of
+// the registry given to Publish and is expected to serve plain HTTP.
+func (app *Application) WithRegistryService(svc *dagger.Service) *Application {
+	app.RegistryService = svc
+	return app
+}
+
+// withRegistry binds the registry service, if any, to the container.
+func (app *Application) withRegistry(ctr *dagger.Container, registry string) *dagger.Container {
+	if app.RegistryService == nil {
+		return ctr
+	}
+
+	host, _, _ := strings.Cut(registry, "/")
+	host, _, _ = strings.Cut(host, ":")
+
+	return ctr.WithServiceBinding(host, app.RegistryService)
+}
+
+// cosign returns a container which runs cosign after logging in to the
+// registry. Cosign is copied into alpine since its own image has no shell
+// to pass the registry secret to cosign login with.
+func (app *Application) cosign(
+	registry string,
+	registryUsername string,
+	registrySecret *dagger.Secret,
+) *dagger.Container {
+	ctr := dag.Container().
+		From("alpine:3").
+		WithFile("/usr/local/bin/cosign", dag.Container().From(cosignImage).File("/ko-app/cosign")).
+		WithEnvVariable("REGISTRY", registry)
+
+	if registrySecret != nil {
+		ctr = ctr.
+			WithEnvVariable("REGISTRY_USERNAME", registryUsername).
+			WithSecretVariable("REGISTRY_PASSWORD", registrySecret)
+	}
+
+	return app.withRegistry(ctr, registry)
+}
+
+func (app *Application) cosignExec(ctr *dagger.Container, args ...string) *dagger.Container {
+	if app.RegistryService != nil {
+		args = append(args, "--allow-http-registry")
+	}
+
+	// The password is piped from the printf builtin so it never appears
+	// in the arguments of a process.
+	script := `if [ -n "$REGISTRY_PASSWORD" ]; then printf '%s' "$REGISTRY_PASSWORD" | cosign login "$REGISTRY" -u "$REGISTRY_USERNAME" --password-stdin >/dev/null; fi && exec cosign "$@"`
+
+	return ctr.WithExec(append([]string{"sh", "-c", script, "cosign"}, args...))
+}
+
+// sign signs the published image and, if given, attests its provenance.
+func (app *Application) sign(
+	ctx context.Context,
+	ref string,
+	registry string,
+	registryUsername string,
+	registrySecret *dagger.Secret,
+	provenance *dagger.File,
+) error {
+	ctr := app.cosign(registry, registryUsername, registrySecret).
+		WithSecretVariable("COSIGN_PRIVATE_KEY", app.SigningKey)
+
+	if app.SigningPassword != nil {
+		ctr = ctr.WithSecretVariable("COSIGN_PASSWORD", app.SigningPassword)
+	} else {
+		ctr = ctr.WithEnvVariable("COSIGN_PASSWORD", "")
+	}
+
+	tlog := "--tlog-upload=false"
+	if app.TlogUpload {
+		tlog = "--tlog-upload=true"
+	}
+
+	ctr = app.cosignExec(ctr, "sign", "--yes", "--key", "env://COSIGN_PRIVATE_KEY", tlog, ref)
+
+	if provenance != nil {
+		ctr = app.cosignExec(
+			ctr.WithMountedFile("/provenance.json", provenance),
+			"attest",
+			"--yes",
+			"--key",
+			"env://COSIGN_PRIVATE_KEY",
+			"--type",
+			"slsaprovenance1",
+			"--predicate",
+			"/provenance.json",
+			tlog,
+			ref,
+		)
+	}
+
+	_, err := ctr.Sync(ctx)
+	return err
+}
+
+// Verify the signature, and optionally the provenance attestation, of a
+// published image.
+func (app *Application) Verify(
+	ctx context.Context,
+
+	// Image reference, e.g. registry/image@sha256:...
+	ref string,
+
+	// Cosign public key matching the signing key.
+	publicKey *dagger.File,
+
+	// Also verify a SLSA provenance attestation.
+	// +optional
+	provenance bool,
+
+	// +optional
+	registryUsername string,
+
+	// +optional
+	registrySecret *dagger.Secret,
+) error {
+	registry, _, _ := strings.Cut(ref, "/")
+
+	ctr := app.cosign(registry, registryUsername, registrySecret).
+		WithMountedFile("/cosign.pub", publicKey)
+
+	tlog := "--insecure-ignore-tlog=true"
+	if app.TlogUpload {
+		tlog = "--insecure-ignore-tlog=false"
+	}
+
+	ctr = app.cosignExec(ctr, "verify", "--key", "/cosign.pub", tlog, ref)
+
+	if provenance {
+		ctr = app.cosignExec(ctr, "verify-attestation", "--key", "/cosign.pub", "--type", "slsaprovenance1", tlog, ref)
+	}
+
+	_, err := ctr.Sync(ctx)
+	return err
+}
+
+// slsaProvenance is a SLSA v1 provenance predicate.
+// See https://slsa.dev/spec/v1.0/provenance
+type slsaProvenance struct {
+	BuildDefinition struct {
+		BuildType            string                   `json:"buildType"`
+		ExternalParameters   map[string]any           `json:"externalParameters"`
+		InternalParameters   map[string]any           `json:"internalParameters"`
+		ResolvedDependencies []slsaResourceDescriptor `json:"resolvedDependencies"`
+	} `json:"buildDefinition"`
+	RunDetails struct {
+		Builder struct {
+			ID string `json:"id"`
+		} `json:"builder"`
+	} `json:"runDetails"`
+}
+
+type slsaResourceDescriptor struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Generate a SLSA provenance predicate describing the source, build flags
+// and Go version used to build the application container image(s).
+func (app *Application) Provenance(
+	ctx context.Context,
+
+	// +default=["-s", "-w"]
+	ldflags []string,
+
+	// +optional
+	tags []string,
+
+	// +default=true
+	trimpath bool,
+
+	// +optional
+	enableCGO bool,
+
+	// +default=["linux/amd64", "linux/arm64"]
+	platforms []dagger.Platform,
+) (*dagger.File, error) {
+	mod := app.Library.Module
+
+	goVersion, err := mod.Ctr.WithExec([]string{"go", "env", "GOVERSION"}).Stdout(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := mod.Ctr.Directory("/src").Digest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	algorithm, hex, found := strings.Cut(source, ":")
+	if !found {
+		return nil, errors.New("malformed source digest: " + source)
+	}
+
+	var p slsaProvenance
+	p.BuildDefinition.BuildType = "https://github.com/z5labs/daggerverse/go/application/build@v1"
+	p.BuildDefinition.ExternalParameters = map[string]any{
+		"mainPackagePath": app.MainPackagePath,
+		"ldflags":         ldflags,
+		"tags":            tags,
+		"trimpath":        trimpath,
+		"enableCGO":       enableCGO,
+		"platforms":       platforms,
+	}
+	p.BuildDefinition.InternalParameters = map[string]any{
+		"goVersion": strings.TrimSpace(goVersion),
+	}
+	p.BuildDefinition.ResolvedDependencies = []slsaResourceDescriptor{
+		{
+			Name: "source",
+			Digest: map[string]string{
+				algorithm: hex,
+			},
+		},
+	}
+	p.RunDetails.Builder.ID = "https://github.com/z5labs/daggerverse/go"
+
+	b, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return dag.File("provenance.json", string(b)), nil
+}
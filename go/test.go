@@ -5,7 +5,15 @@
 
 package main
 
-import "dagger/go/internal/dagger"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"dagger/go/internal/dagger"
+	"dagger/go/internal/testjson"
+)
 
 type Test struct {
 	// +private
@@ -20,6 +28,7 @@ type Test struct {
 
 // Run tests within a Go module.
 func (m *Mod) Test(
+	// Package pattern, or multiple patterns separated by spaces.
 	pkg string,
 
 	// +optional
@@ -45,14 +54,81 @@ const (
 	Set    CoverageMode = "set"
 )
 
-// Retrieve a coverage profile from running the tests.
+// Retrieve a coverage profile from running the tests, failing with a
+// breakdown of every failed test. The tests run when Coverage is called,
+// rather than when the returned profile is first read, so Go callers now
+// pass a context and handle the error.
 func (t *Test) Coverage(
+	ctx context.Context,
+
+	// +default="set"
+	mode CoverageMode,
+) (*dagger.File, error) {
+	ctr, err := t.exec(ctx, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	return ctr.File("/tmp/cover.out"), nil
+}
+
+// Retrieve the raw test2json event stream from running the tests.
+func (t *Test) Json(
+	// Coverage mode to run the tests with, use the same mode as Coverage
+	// to reuse its test run.
 	// +default="set"
 	mode CoverageMode,
 ) *dagger.File {
+	return t.run(mode).File("/tmp/test.json")
+}
+
+// Retrieve a JUnit XML report from running the tests.
+func (t *Test) Junit(
+	ctx context.Context,
+
+	// Coverage mode to run the tests with, use the same mode as Coverage
+	// to reuse its test run.
+	// +default="set"
+	mode CoverageMode,
+) (*dagger.File, error) {
+	report, err := t.report(ctx, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := testjson.JUnit(report)
+	if err != nil {
+		return nil, err
+	}
+
+	return dag.File("junit.xml", string(b)), nil
+}
+
+// Summarize the results from running the tests.
+func (t *Test) Summary(
+	ctx context.Context,
+
+	// Coverage mode to run the tests with, use the same mode as Coverage
+	// to reuse its test run.
+	// +default="set"
+	mode CoverageMode,
+) (*TestSummary, error) {
+	report, err := t.report(ctx, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTestSummary(report), nil
+}
+
+// run executes the tests, collecting both the coverage profile and the
+// test2json event stream, so every output requested with the same coverage
+// mode shares the same run.
+func (t *Test) run(mode CoverageMode) *dagger.Container {
 	args := []string{
 		"go",
 		"test",
+		"-json",
 		"-coverprofile",
 		"/tmp/cover.out",
 	}
@@ -64,12 +140,149 @@ func (t *Test) Coverage(
 
 	args = append(args, "-covermode", string(mode))
 
-	args = append(args, t.Pkg)
+	args = append(args, strings.Fields(t.Pkg)...)
 
 	out := dag.Directory()
 
 	return t.Ctr.
 		WithDirectory("/tmp", out).
-		WithExec(args).
-		File("/tmp/cover.out")
+		WithExec(args, dagger.ContainerWithExecOpts{
+			RedirectStdout: "/tmp/test.json",
+			Expect:         dagger.ReturnTypeAny,
+		})
+}
+
+// exec runs the tests and fails with a per-test breakdown if any test failed.
+func (t *Test) exec(ctx context.Context, mode CoverageMode) (*dagger.Container, error) {
+	ctr := t.run(mode)
+
+	code, err := ctr.ExitCode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if code == 0 {
+		return ctr, nil
+	}
+
+	contents, err := ctr.File("/tmp/test.json").Contents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := testjson.Parse(strings.NewReader(contents))
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, newTestSummary(report).err(code)
+}
+
+func (t *Test) report(ctx context.Context, mode CoverageMode) (*testjson.Report, error) {
+	contents, err := t.Json(mode).Contents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return testjson.Parse(strings.NewReader(contents))
+}
+
+// TestSummary counts the results of a test run and lists every test ran.
+type TestSummary struct {
+	// Number of tests which passed.
+	Passed int
+
+	// Number of tests which failed.
+	Failed int
+
+	// Number of tests which were skipped.
+	Skipped int
+
+	// Results for every test ran, including subtests.
+	Tests []*TestCase
+
+	// Packages which failed without a failing test, e.g. build failures.
+	FailedPackages []*TestCase
+}
+
+// TestCase is the result of a single test, or of a package which failed
+// without a failing test.
+type TestCase struct {
+	// Import path of the package containing the test.
+	Package string
+
+	// Name of the test.
+	Name string
+
+	// One of pass, fail or skip.
+	Result string
+
+	// Duration of the test in seconds.
+	Elapsed float64
+
+	// Output written by the test.
+	Output string
+}
+
+func newTestSummary(report *testjson.Report) *TestSummary {
+	summary := &TestSummary{}
+
+	for _, pkg := range report.Packages {
+		var failed bool
+		for _, test := range pkg.Tests {
+			switch test.Result {
+			case testjson.ActionPass:
+				summary.Passed++
+			case testjson.ActionFail:
+				summary.Failed++
+				failed = true
+			case testjson.ActionSkip:
+				summary.Skipped++
+			}
+
+			summary.Tests = append(summary.Tests, &TestCase{
+				Package: test.Package,
+				Name:    test.Name,
+				Result:  test.Result,
+				Elapsed: test.Elapsed,
+				Output:  test.Output,
+			})
+		}
+
+		if pkg.Result == testjson.ActionFail && !failed {
+			summary.FailedPackages = append(summary.FailedPackages, &TestCase{
+				Package: pkg.Name,
+				Result:  pkg.Result,
+				Elapsed: pkg.Elapsed,
+				Output:  pkg.Output,
+			})
+		}
+	}
+
+	return summary
+}
+
+// Only report tests which failed.
+func (s *TestSummary) Failures() []*TestCase {
+	var failures []*TestCase
+	for _, tc := range s.Tests {
+		if tc.Result == testjson.ActionFail {
+			failures = append(failures, tc)
+		}
+	}
+	return failures
+}
+
+func (s *TestSummary) err(code int) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "go test exited with code %d: %d passed, %d failed, %d skipped\n", code, s.Passed, s.Failed, s.Skipped)
+
+	for _, tc := range s.FailedPackages {
+		fmt.Fprintf(&sb, "\n--- FAIL: %s\n%s", tc.Package, tc.Output)
+	}
+
+	for _, tc := range s.Failures() {
+		fmt.Fprintf(&sb, "\n--- FAIL: %s.%s (%.2fs)\n%s", tc.Package, tc.Name, tc.Elapsed, tc.Output)
+	}
+
+	return errors.New(sb.String())
 }
@@ -0,0 +1,182 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"dagger/gotests/internal/dagger"
+
+	"github.com/sourcegraph/conc/pool"
+)
+
+type Affected struct {
+	// +private
+	Go *dagger.Go
+}
+
+func (m *GoTests) Affected() *Affected {
+	return &Affected{
+		Go: m.Go,
+	}
+}
+
+func (a *Affected) All(ctx context.Context) error {
+	ep := pool.New().WithErrors().WithContext(ctx)
+
+	ep.Go(a.BaseDirectoryTest)
+	ep.Go(a.ReadmeTest)
+	ep.Go(a.GoModTest)
+	ep.Go(a.EmbedTest)
+	ep.Go(a.NestedFileTest)
+	ep.Go(a.DeletedFileTest)
+	ep.Go(a.DeletedPackageTest)
+	ep.Go(a.CiTest)
+
+	return ep.Wait()
+}
+
+func (a *Affected) base() *dagger.Directory {
+	return dag.CurrentModule().Source().Directory("testdata/workspace/affected")
+}
+
+// packages returns the affected packages of each affected module.
+func packages(ctx context.Context, affected *dagger.GoAffected) (map[string][]string, error) {
+	modules, err := affected.Modules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pkgs := make(map[string][]string, len(modules))
+	for _, module := range modules {
+		path, err := module.Path(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		pkgs[path], err = module.Packages(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return pkgs, nil
+}
+
+func expectPackages(ctx context.Context, affected *dagger.GoAffected, expected map[string][]string) error {
+	pkgs, err := packages(ctx, affected)
+	if err != nil {
+		return err
+	}
+
+	if !maps.EqualFunc(pkgs, expected, slices.Equal) {
+		return fmt.Errorf("unexpected affected packages: %v != %v", pkgs, expected)
+	}
+
+	return nil
+}
+
+// A change in one module must affect the packages importing it from
+// another module, but not unrelated packages.
+func (a *Affected) BaseDirectoryTest(ctx context.Context) error {
+	head := a.base().WithNewFile("lib/strs/exclaim.go", "package strs\n\nconst exclamation = \"!\"\n")
+
+	affected := a.Go.Workspace(head).Affected(dagger.GoWorkspaceAffectedOpts{
+		Base: a.base(),
+	})
+
+	return expectPackages(ctx, affected, map[string][]string{
+		"app": {"example.com/app"},
+		"lib": {"example.com/lib/strs"},
+	})
+}
+
+func (a *Affected) ReadmeTest(ctx context.Context) error {
+	affected := a.Go.Workspace(a.base()).Affected(dagger.GoWorkspaceAffectedOpts{
+		ChangedFiles: []string{"README.md"},
+	})
+
+	return expectPackages(ctx, affected, map[string][]string{})
+}
+
+func (a *Affected) GoModTest(ctx context.Context) error {
+	affected := a.Go.Workspace(a.base()).Affected(dagger.GoWorkspaceAffectedOpts{
+		ChangedFiles: []string{"lib/go.mod"},
+	})
+
+	return expectPackages(ctx, affected, map[string][]string{
+		"app": {"example.com/app"},
+		"lib": {"example.com/lib/nums", "example.com/lib/strs"},
+	})
+}
+
+// Embedded files are not in the package directory itself, yet changing
+// them affects the package embedding them and its dependents.
+func (a *Affected) EmbedTest(ctx context.Context) error {
+	head := a.base().WithNewFile("lib/strs/templates/suffix.txt", "?")
+
+	affected := a.Go.Workspace(head).Affected(dagger.GoWorkspaceAffectedOpts{
+		Base: a.base(),
+	})
+
+	return expectPackages(ctx, affected, map[string][]string{
+		"app": {"example.com/app"},
+		"lib": {"example.com/lib/strs"},
+	})
+}
+
+// Files in subdirectories which are not packages belong to the package
+// in the closest parent directory.
+func (a *Affected) NestedFileTest(ctx context.Context) error {
+	affected := a.Go.Workspace(a.base()).Affected(dagger.GoWorkspaceAffectedOpts{
+		ChangedFiles: []string{"lib/nums/docs/usage.md"},
+	})
+
+	return expectPackages(ctx, affected, map[string][]string{
+		"lib": {"example.com/lib/nums"},
+	})
+}
+
+func (a *Affected) DeletedFileTest(ctx context.Context) error {
+	head := a.base().WithoutFile("lib/strs/strs_test.go")
+
+	affected := a.Go.Workspace(head).Affected(dagger.GoWorkspaceAffectedOpts{
+		Base: a.base(),
+	})
+
+	return expectPackages(ctx, affected, map[string][]string{
+		"app": {"example.com/app"},
+		"lib": {"example.com/lib/strs"},
+	})
+}
+
+// A deleted package is no longer listed, yet the packages which imported
+// it are affected.
+func (a *Affected) DeletedPackageTest(ctx context.Context) error {
+	head := a.base().WithoutDirectory("lib/strs")
+
+	affected := a.Go.Workspace(head).Affected(dagger.GoWorkspaceAffectedOpts{
+		Base: a.base(),
+	})
+
+	return expectPackages(ctx, affected, map[string][]string{
+		"app": {"example.com/app"},
+	})
+}
+
+func (a *Affected) CiTest(ctx context.Context) error {
+	return a.Go.Workspace(a.base()).
+		Affected(dagger.GoWorkspaceAffectedOpts{
+			ChangedFiles: []string{"lib/nums/nums.go"},
+		}).
+		Ci(dagger.GoAffectedCiOpts{
+			Linter: dag.Noop().GoLinter().AsGoLinter(),
+		}).
+		Check(ctx)
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	"dagger/gotests/internal/dagger"
 
@@ -31,6 +32,8 @@ func (b *Build) All(ctx context.Context) error {
 
 	ep.Go(b.WithDefaultsTest)
 	ep.Go(b.WithOptionsTest)
+	ep.Go(b.MatrixTest)
+	ep.Go(b.MatrixWithNameTest)
 
 	return ep.Wait()
 }
@@ -89,3 +92,53 @@ func (b *Build) WithOptionsTest(ctx context.Context) error {
 
 	return nil
 }
+
+func (b *Build) MatrixTest(ctx context.Context) error {
+	dir := b.Go.Module(dag.CurrentModule().Source().Directory("testdata/buildoutput")).
+		BuildMatrix(".")
+
+	entries, err := dir.Entries(ctx)
+	if err != nil {
+		return err
+	}
+
+	expected := []string{
+		"buildoutput_darwin_amd64",
+		"buildoutput_darwin_arm64",
+		"buildoutput_linux_amd64",
+		"buildoutput_linux_arm64",
+		"buildoutput_windows_amd64.exe",
+	}
+
+	slices.Sort(entries)
+	if !slices.Equal(entries, expected) {
+		return fmt.Errorf("unexpected binaries: %v", entries)
+	}
+
+	return nil
+}
+
+func (b *Build) MatrixWithNameTest(ctx context.Context) error {
+	dir := b.Go.Module(dag.CurrentModule().Source().Directory("testdata/buildoutput")).
+		BuildMatrix(".", dagger.GoModBuildMatrixOpts{
+			Name:      "app",
+			Platforms: []dagger.Platform{"linux/arm/v6", "windows/arm64"},
+		})
+
+	entries, err := dir.Entries(ctx)
+	if err != nil {
+		return err
+	}
+
+	expected := []string{
+		"app_linux_armv6",
+		"app_windows_arm64.exe",
+	}
+
+	slices.Sort(entries)
+	if !slices.Equal(entries, expected) {
+		return fmt.Errorf("unexpected binaries: %v", entries)
+	}
+
+	return nil
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+
+	"dagger/gotests/internal/dagger"
+
+	"github.com/sourcegraph/conc/pool"
+)
+
+type Coverage struct {
+	// +private
+	Go *dagger.Go
+}
+
+func (m *GoTests) Coverage() *Coverage {
+	return &Coverage{
+		Go: m.Go,
+	}
+}
+
+func (c *Coverage) All(ctx context.Context) error {
+	ep := pool.New().WithErrors().WithContext(ctx)
+
+	ep.Go(c.SummaryTest)
+	ep.Go(c.CheckTest)
+	ep.Go(c.CheckBelowMinimumTest)
+	ep.Go(c.CheckBelowPackageMinimumTest)
+	ep.Go(c.CoberturaTest)
+	ep.Go(c.CoberturaTimestampTest)
+	ep.Go(c.HtmlTest)
+
+	return ep.Wait()
+}
+
+func (c *Coverage) coverage() *dagger.GoCoverage {
+	mod := c.Go.Module(dag.CurrentModule().Source().Directory("testdata/coverage"))
+
+	return mod.Coverage(mod.Test("./...").Coverage())
+}
+
+func (c *Coverage) SummaryTest(ctx context.Context) error {
+	summary := c.coverage().Summary()
+
+	total, err := summary.Total(ctx)
+	if err != nil {
+		return err
+	}
+
+	if total != 50 {
+		return fmt.Errorf("expected total coverage of 50%% but received: %.1f%%", total)
+	}
+
+	pkgs, err := summary.Packages(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(pkgs) != 1 {
+		return fmt.Errorf("expected coverage for only 1 package but received: %d", len(pkgs))
+	}
+
+	name, err := pkgs[0].Name(ctx)
+	if err != nil {
+		return err
+	}
+
+	if name != "coverage" {
+		return errors.New("unexpected package name: " + name)
+	}
+
+	files, err := summary.Files(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(files) != 1 {
+		return fmt.Errorf("expected coverage for only 1 file but received: %d", len(files))
+	}
+
+	return nil
+}
+
+func (c *Coverage) CheckTest(ctx context.Context) error {
+	return c.coverage().Check(ctx, dagger.GoCoverageCheckOpts{
+		Minimum:        50,
+		PackageMinimum: 50,
+	})
+}
+
+func (c *Coverage) CheckBelowMinimumTest(ctx context.Context) error {
+	err := c.coverage().Check(ctx, dagger.GoCoverageCheckOpts{
+		Minimum: 80,
+	})
+	if err == nil {
+		return errors.New("expected check to fail due to total coverage being below minimum")
+	}
+
+	if !strings.Contains(err.Error(), "total coverage 50.0% is below minimum of 80.0%") {
+		return errors.New("unexpected error message: " + err.Error())
+	}
+
+	return nil
+}
+
+func (c *Coverage) CheckBelowPackageMinimumTest(ctx context.Context) error {
+	err := c.coverage().Check(ctx, dagger.GoCoverageCheckOpts{
+		PackageMinimum: 80,
+	})
+	if err == nil {
+		return errors.New("expected check to fail due to package coverage being below minimum")
+	}
+
+	if !strings.Contains(err.Error(), "package coverage coverage 50.0% is below minimum of 80.0%") {
+		return errors.New("unexpected error message: " + err.Error())
+	}
+
+	return nil
+}
+
+func (c *Coverage) CoberturaTest(ctx context.Context) error {
+	contents, err := c.coverage().Cobertura().Contents(ctx)
+	if err != nil {
+		return err
+	}
+
+	var report struct {
+		Timestamp    int64    `xml:"timestamp,attr"`
+		LinesCovered int      `xml:"lines-covered,attr"`
+		LinesValid   int      `xml:"lines-valid,attr"`
+		Sources      []string `xml:"sources>source"`
+		Packages     []struct {
+			Name    string `xml:"name,attr"`
+			Classes []struct {
+				Filename string `xml:"filename,attr"`
+			} `xml:"classes>class"`
+		} `xml:"packages>package"`
+	}
+	err = xml.Unmarshal([]byte(contents), &report)
+	if err != nil {
+		return err
+	}
+
+	if report.Timestamp != 0 {
+		return fmt.Errorf("expected the default timestamp to be 0: %d", report.Timestamp)
+	}
+
+	if report.LinesValid == 0 || report.LinesCovered*2 != report.LinesValid {
+		return fmt.Errorf("unexpected line coverage (covered=%d, valid=%d)", report.LinesCovered, report.LinesValid)
+	}
+
+	if len(report.Packages) != 1 || report.Packages[0].Name != "coverage" {
+		return fmt.Errorf("expected a single package named coverage: %v", report.Packages)
+	}
+
+	// File names must be relative to the module root so they resolve
+	// against the source directory.
+	if len(report.Sources) != 1 || report.Sources[0] != "/src" {
+		return fmt.Errorf("expected the module directory as source: %v", report.Sources)
+	}
+	for _, class := range report.Packages[0].Classes {
+		if class.Filename != "coverage.go" {
+			return fmt.Errorf("expected file name relative to the module root: %s", class.Filename)
+		}
+	}
+
+	return nil
+}
+
+func (c *Coverage) CoberturaTimestampTest(ctx context.Context) error {
+	contents, err := c.coverage().Cobertura(dagger.GoCoverageCoberturaOpts{
+		Timestamp: 1700000000,
+	}).Contents(ctx)
+	if err != nil {
+		return err
+	}
+
+	var report struct {
+		Timestamp int64 `xml:"timestamp,attr"`
+	}
+	err = xml.Unmarshal([]byte(contents), &report)
+	if err != nil {
+		return err
+	}
+
+	// Cobertura records the timestamp in milliseconds.
+	if report.Timestamp != 1700000000000 {
+		return fmt.Errorf("expected the given timestamp in milliseconds: %d", report.Timestamp)
+	}
+
+	return nil
+}
+
+func (c *Coverage) HtmlTest(ctx context.Context) error {
+	contents, err := c.coverage().Html().Contents(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(contents, "coverage/coverage.go") {
+		return errors.New("expected html report to reference covered source file")
+	}
+
+	return nil
+}
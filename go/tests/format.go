@@ -0,0 +1,138 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"dagger/gotests/internal/dagger"
+
+	"github.com/sourcegraph/conc/pool"
+)
+
+type Format struct {
+	// +private
+	Go *dagger.Go
+}
+
+func (m *GoTests) Format() *Format {
+	return &Format{
+		Go: m.Go,
+	}
+}
+
+func (f *Format) All(ctx context.Context) error {
+	ep := pool.New().WithErrors().WithContext(ctx)
+
+	ep.Go(f.CheckGofmtTest)
+	ep.Go(f.ApplyGofmtTest)
+	ep.Go(f.GoimportsLocalPrefixTest)
+	ep.Go(f.GofumptTest)
+	ep.Go(f.LocalPrefixUnsupportedTest)
+
+	return ep.Wait()
+}
+
+func (f *Format) module(name string) *dagger.GoMod {
+	return f.Go.Module(dag.CurrentModule().Source().Directory("testdata/format/" + name))
+}
+
+func (f *Format) CheckGofmtTest(ctx context.Context) error {
+	format := f.module("gofmt").Format()
+
+	files, err := format.Files(ctx)
+	if err != nil {
+		return err
+	}
+	if !slices.Equal(files, []string{"add.go"}) {
+		return fmt.Errorf("expected only add.go to be unformatted: %v", files)
+	}
+
+	err = format.Check(ctx)
+	if err == nil {
+		return errors.New("expected check to fail on unformatted files")
+	}
+	if !strings.Contains(err.Error(), "+\treturn a + b") {
+		return fmt.Errorf("expected check to include a diff: %w", err)
+	}
+
+	return nil
+}
+
+func (f *Format) ApplyGofmtTest(ctx context.Context) error {
+	formatted := f.module("gofmt").Format().Apply()
+
+	return f.Go.Module(formatted).Format().Check(ctx)
+}
+
+func (f *Format) GoimportsLocalPrefixTest(ctx context.Context) error {
+	mod := f.module("strict")
+
+	err := mod.Format(dagger.GoModFormatOpts{
+		Formatter: dagger.GoFormatterGoimports,
+	}).Check(ctx)
+	if err != nil {
+		return err
+	}
+
+	format := mod.Format(dagger.GoModFormatOpts{
+		Formatter:   dagger.GoFormatterGoimports,
+		LocalPrefix: "example.com/strict",
+	})
+
+	files, err := format.Files(ctx)
+	if err != nil {
+		return err
+	}
+	if !slices.Equal(files, []string{"main.go"}) {
+		return fmt.Errorf("expected local imports of main.go to be regrouped: %v", files)
+	}
+
+	contents, err := format.Apply().File("main.go").Contents(ctx)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(contents, "\"fmt\"\n\n\t\"example.com/strict/internal/greeting\"") {
+		return fmt.Errorf("expected local imports after standard library imports:\n%s", contents)
+	}
+
+	return nil
+}
+
+func (f *Format) GofumptTest(ctx context.Context) error {
+	mod := f.module("strict")
+
+	err := mod.Format().Check(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = mod.Format(dagger.GoModFormatOpts{
+		Formatter: dagger.GoFormatterGofumpt,
+	}).Check(ctx)
+	if err == nil {
+		return errors.New("expected gofumpt to be stricter than gofmt")
+	}
+
+	return nil
+}
+
+func (f *Format) LocalPrefixUnsupportedTest(ctx context.Context) error {
+	_, err := f.module("strict").
+		Format(dagger.GoModFormatOpts{
+			LocalPrefix: "example.com/strict",
+		}).
+		Files(ctx)
+	if err == nil {
+		return errors.New("expected local prefix to be rejected by gofmt")
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"dagger/gotests/internal/dagger"
+
+	"github.com/sourcegraph/conc/pool"
+)
+
+type Image struct {
+	// +private
+	Go *dagger.Go
+}
+
+func (m *GoTests) Image() *Image {
+	return &Image{
+		Go: m.Go,
+	}
+}
+
+func (i *Image) All(ctx context.Context) error {
+	ep := pool.New().WithErrors().WithContext(ctx)
+
+	ep.Go(i.ScratchTest)
+	ep.Go(i.BaseImageTest)
+	ep.Go(i.BaseContainerPlatformTest)
+	ep.Go(i.MetadataTest)
+	ep.Go(i.ConfigTest)
+	ep.Go(i.CaCertificatesAndTzdataTest)
+	ep.Go(i.InvalidNameTest)
+
+	return ep.Wait()
+}
+
+func (i *Image) application() *dagger.GoApplication {
+	return i.Go.Module(dag.CurrentModule().Source().Directory("testdata/buildoutput")).
+		Library().
+		Application(".")
+}
+
+// build returns the image built for linux/amd64.
+func buildImage(ctx context.Context, app *dagger.GoApplication) (*dagger.Container, error) {
+	variants, err := app.Build(ctx, dagger.GoApplicationBuildOpts{
+		Platforms: []dagger.Platform{"linux/amd64"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(variants) != 1 {
+		return nil, fmt.Errorf("expected a single platform variant: %d", len(variants))
+	}
+
+	return &variants[0], nil
+}
+
+func (i *Image) ScratchTest(ctx context.Context) error {
+	ctr, err := buildImage(ctx, i.application())
+	if err != nil {
+		return err
+	}
+
+	entries, err := ctr.Rootfs().Entries(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Equal(entries, []string{"main"}) {
+		return fmt.Errorf("expected scratch image to only contain the binary: %v", entries)
+	}
+
+	return nil
+}
+
+func (i *Image) BaseImageTest(ctx context.Context) error {
+	ctr, err := buildImage(ctx, i.application().WithBaseImage("alpine"))
+	if err != nil {
+		return err
+	}
+
+	out, err := ctr.WithExec([]string{"/main"}).Stdout(ctx)
+	if err != nil {
+		return err
+	}
+
+	if out != "hello world\n" {
+		return fmt.Errorf("unexpected output from binary in alpine image: %q", out)
+	}
+
+	_, err = ctr.File("/etc/alpine-release").Sync(ctx)
+	return err
+}
+
+func (i *Image) BaseContainerPlatformTest(ctx context.Context) error {
+	base := dag.Container(dagger.ContainerOpts{
+		Platform: "linux/arm64",
+	}).From("alpine:3")
+
+	_, err := buildImage(ctx, i.application().WithBaseContainer(base))
+	if err == nil {
+		return errors.New("expected build to fail for a base container of another platform")
+	}
+
+	return nil
+}
+
+func (i *Image) MetadataTest(ctx context.Context) error {
+	ctr, err := buildImage(ctx, i.application().
+		WithMetadata(dagger.GoApplicationWithMetadataOpts{
+			Source:   "https://github.com/z5labs/daggerverse",
+			Revision: "abc123",
+			Version:  "v1.2.3",
+		}).
+		WithLabel("com.example.team", "platform"))
+	if err != nil {
+		return err
+	}
+
+	expected := map[string]string{
+		"org.opencontainers.image.source":   "https://github.com/z5labs/daggerverse",
+		"org.opencontainers.image.revision": "abc123",
+		"org.opencontainers.image.version":  "v1.2.3",
+		"com.example.team":                  "platform",
+	}
+	for name, value := range expected {
+		label, err := ctr.Label(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		if label != value {
+			return fmt.Errorf("expected label %s to be %q: %q", name, value, label)
+		}
+	}
+
+	created, err := ctr.Label(ctx, "org.opencontainers.image.created")
+	if err != nil {
+		return err
+	}
+	if created != "" {
+		return fmt.Errorf("expected no created label by default: %q", created)
+	}
+
+	return nil
+}
+
+func (i *Image) ConfigTest(ctx context.Context) error {
+	ctr, err := buildImage(ctx, i.application().
+		WithUser().
+		WithExposedPort(8080).
+		WithEnvVariable("LOG_LEVEL", "debug").
+		WithWorkdir("/app"))
+	if err != nil {
+		return err
+	}
+
+	user, err := ctr.User(ctx)
+	if err != nil {
+		return err
+	}
+	if user != "65532:65532" {
+		return fmt.Errorf("expected non-root user: %q", user)
+	}
+
+	ports, err := ctr.ExposedPorts(ctx)
+	if err != nil {
+		return err
+	}
+	if len(ports) != 1 {
+		return fmt.Errorf("expected a single exposed port: %d", len(ports))
+	}
+
+	port, err := ports[0].Port(ctx)
+	if err != nil {
+		return err
+	}
+	if port != 8080 {
+		return fmt.Errorf("expected port 8080 to be exposed: %d", port)
+	}
+
+	env, err := ctr.EnvVariable(ctx, "LOG_LEVEL")
+	if err != nil {
+		return err
+	}
+	if env != "debug" {
+		return fmt.Errorf("expected LOG_LEVEL to be debug: %q", env)
+	}
+
+	workdir, err := ctr.Workdir(ctx)
+	if err != nil {
+		return err
+	}
+	if workdir != "/app" {
+		return fmt.Errorf("expected workdir to be /app: %q", workdir)
+	}
+
+	return nil
+}
+
+func (i *Image) CaCertificatesAndTzdataTest(ctx context.Context) error {
+	ctr, err := buildImage(ctx, i.application().
+		WithCaCertificates().
+		WithTzdata())
+	if err != nil {
+		return err
+	}
+
+	_, err = ctr.File("/etc/ssl/certs/ca-certificates.crt").Sync(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = ctr.File("/usr/share/zoneinfo/America/New_York").Sync(ctx)
+	return err
+}
+
+func (i *Image) InvalidNameTest(ctx context.Context) error {
+	for _, name := range []string{"", "com.example.team=platform"} {
+		apps := map[string]*dagger.GoApplication{
+			"label":                i.application().WithLabel(name, "value"),
+			"annotation":           i.application().WithAnnotation(name, "value"),
+			"environment variable": i.application().WithEnvVariable(name, "value"),
+		}
+
+		for kind, app := range apps {
+			_, err := app.Sync(ctx)
+			if err == nil {
+				return fmt.Errorf("expected %s name to be rejected: %q", kind, name)
+			}
+			if !strings.Contains(err.Error(), "invalid "+kind+" name") {
+				return fmt.Errorf("expected invalid %s name error for %q: %w", kind, name, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -8,6 +8,9 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 
 	"dagger/gotests/internal/dagger"
 
@@ -29,10 +32,19 @@ func (t *Library) All(ctx context.Context) error {
 	ep := pool.New().WithErrors().WithContext(ctx)
 
 	ep.Go(t.CiTest)
+	ep.Go(t.CiCoverageThresholdTest)
+	ep.Go(t.CiLintSeverityTest)
+	ep.Go(t.CiVulnerableDependencyTest)
+	ep.Go(t.CiVulncheckTest)
+	ep.Go(t.CiFormatTest)
+	ep.Go(t.LintCheckTest)
+	ep.Go(t.LintCheckBelowSeverityTest)
 	ep.Go(t.TidyTest)
 	ep.Go(t.GenerateTest)
 	ep.Go(t.GenerateContentDiffTest)
 	ep.Go(t.GenerateNoDiffTest)
+	ep.Go(t.GenerateApplyTest)
+	ep.Go(t.TidyApplyTest)
 
 	return ep.Wait()
 }
@@ -48,6 +60,154 @@ func (l *Library) CiTest(ctx context.Context) error {
 		Ci(ctx)
 }
 
+func (l *Library) CiCoverageThresholdTest(ctx context.Context) error {
+	err := l.Go.Module(dag.CurrentModule().Source().Directory("testdata/coverage")).
+		Library(
+			dagger.GoModLibraryOpts{
+				Linter:         dag.Noop().GoLinter().AsGoLinter(),
+				StaticAnalyzer: dag.Noop().GoStaticAnalyzer().AsGoStaticAnalyzer(),
+			},
+		).
+		WithCoverageThreshold(dagger.GoLibraryWithCoverageThresholdOpts{
+			Total: 80,
+		}).
+		Ci(ctx)
+
+	if err == nil {
+		return errors.New("expected ci to fail due to insufficient coverage")
+	}
+	if !strings.Contains(err.Error(), "insufficient test coverage") {
+		return fmt.Errorf("expected ci to fail due to insufficient coverage: %w", err)
+	}
+
+	return nil
+}
+
+func (l *Library) CiVulnerableDependencyTest(ctx context.Context) error {
+	err := l.Go.Module(dag.CurrentModule().Source().Directory("testdata/library/vulnerable-dependency")).
+		Library(
+			dagger.GoModLibraryOpts{
+				Linter:            dag.Noop().GoLinter().AsGoLinter(),
+				StaticAnalyzer:    dag.Noop().GoStaticAnalyzer().AsGoStaticAnalyzer(),
+				DependencyScanner: dag.Trivy().AsGoDependencyScanner(),
+			},
+		).
+		Ci(ctx)
+
+	if err == nil {
+		return errors.New("expected ci to fail due to a vulnerable dependency")
+	}
+	if !strings.Contains(err.Error(), "golang.org/x/text") {
+		return errors.New("expected ci to report the vulnerable dependency: " + err.Error())
+	}
+
+	return nil
+}
+
+func (l *Library) CiVulncheckTest(ctx context.Context) error {
+	err := l.Go.Module(dag.CurrentModule().Source().Directory("testdata/library/vulnerable-dependency")).
+		Library(
+			dagger.GoModLibraryOpts{
+				Linter:         dag.Noop().GoLinter().AsGoLinter(),
+				StaticAnalyzer: dag.Noop().GoStaticAnalyzer().AsGoStaticAnalyzer(),
+			},
+		).
+		WithVulncheck(dagger.GoLibraryWithVulncheckOpts{
+			Db:     l.Go.VulnDb(),
+			Binary: l.Go.Govulncheck(),
+		}).
+		Ci(ctx)
+
+	if err == nil {
+		return errors.New("expected ci to fail due to a reachable vulnerability")
+	}
+	if !strings.Contains(err.Error(), "GO-2021-0113") {
+		return errors.New("expected ci to report the reachable vulnerability: " + err.Error())
+	}
+
+	return nil
+}
+
+func (l *Library) CiFormatTest(ctx context.Context) error {
+	err := l.Go.Module(dag.CurrentModule().Source().Directory("testdata/format/gofmt")).
+		Library(
+			dagger.GoModLibraryOpts{
+				Linter:         dag.Noop().GoLinter().AsGoLinter(),
+				StaticAnalyzer: dag.Noop().GoStaticAnalyzer().AsGoStaticAnalyzer(),
+			},
+		).
+		WithFormat().
+		Ci(ctx)
+
+	if err == nil {
+		return errors.New("expected ci to fail due to unformatted files")
+	}
+	if !strings.Contains(err.Error(), "not formatted by gofmt") {
+		return fmt.Errorf("expected ci to report the unformatted files: %w", err)
+	}
+
+	return nil
+}
+
+func (l *Library) CiLintSeverityTest(ctx context.Context) error {
+	err := l.Go.Module(dag.CurrentModule().Source().Directory("testdata/library/ci")).
+		Library(
+			dagger.GoModLibraryOpts{
+				Linter: dag.Const().GoLinter(dag.File("report.txt", ""), dagger.ConstGoLinterOpts{
+					Severity: []string{"error"},
+				}).AsGoLinter(),
+				StaticAnalyzer: dag.Noop().GoStaticAnalyzer().AsGoStaticAnalyzer(),
+			},
+		).
+		WithLintSeverity(dagger.GoLintSeverityError).
+		Ci(ctx)
+
+	if err == nil {
+		return errors.New("expected ci to fail due to lint findings")
+	}
+
+	return nil
+}
+
+func (l *Library) LintCheckTest(ctx context.Context) error {
+	lib := l.Go.Module(dag.CurrentModule().Source().Directory("testdata/library/ci")).
+		Library(
+			dagger.GoModLibraryOpts{
+				Linter: dag.Const().GoLinter(dag.File("report.txt", ""), dagger.ConstGoLinterOpts{
+					Severity: []string{"info", "warning"},
+				}).AsGoLinter(),
+				StaticAnalyzer: dag.Noop().GoStaticAnalyzer().AsGoStaticAnalyzer(),
+			},
+		)
+
+	err := lib.LintCheck(ctx, lib.Lint(), dagger.GoLibraryLintCheckOpts{
+		Severity: dagger.GoLintSeverityWarning,
+	})
+	if err == nil {
+		return errors.New("expected lint check to fail due to warning finding")
+	}
+
+	if !strings.Contains(err.Error(), "found 1 lint finding(s)") {
+		return errors.New("expected only the warning finding to be reported: " + err.Error())
+	}
+
+	return nil
+}
+
+func (l *Library) LintCheckBelowSeverityTest(ctx context.Context) error {
+	lib := l.Go.Module(dag.CurrentModule().Source().Directory("testdata/library/ci")).
+		Library(
+			dagger.GoModLibraryOpts{
+				Linter: dag.Const().GoLinter(dag.File("report.txt", ""), dagger.ConstGoLinterOpts{
+					Severity: []string{"info", "warning"},
+				}).AsGoLinter(),
+				StaticAnalyzer: dag.Noop().GoStaticAnalyzer().AsGoStaticAnalyzer(),
+			},
+		)
+
+	return lib.LintCheck(ctx, lib.Lint())
+}
+
 func (l *Library) TidyTest(ctx context.Context) error {
 	err := l.Go.Module(dag.CurrentModule().Source().Directory("testdata/library/tidy")).
 		Library(
@@ -109,3 +269,43 @@ func (l *Library) GenerateNoDiffTest(ctx context.Context) error {
 		).
 		Generate(ctx)
 }
+
+func (l *Library) GenerateApplyTest(ctx context.Context) error {
+	generate := l.Go.Module(dag.CurrentModule().Source().Directory("testdata/library/generate-content-diff")).
+		Generate(".")
+
+	changed, err := generate.Apply(dagger.GoGenerateApplyOpts{ChangedOnly: true}).Entries(ctx)
+	if err != nil {
+		return err
+	}
+	if !slices.Equal(changed, []string{"generated.txt"}) {
+		return fmt.Errorf("expected only generated.txt to be changed: %v", changed)
+	}
+
+	return l.Go.Module(generate.Apply()).
+		Generate(".").
+		Diff(ctx)
+}
+
+func (l *Library) TidyApplyTest(ctx context.Context) error {
+	tidy := l.Go.Module(dag.CurrentModule().Source().Directory("testdata/library/tidy")).
+		Tidy()
+
+	changed, err := tidy.Apply(dagger.GoTidyApplyOpts{ChangedOnly: true}).Entries(ctx)
+	if err != nil {
+		return err
+	}
+	if !slices.Equal(changed, []string{"go.mod", "go.sum"}) {
+		return fmt.Errorf("expected only go.mod and go.sum to be changed: %v", changed)
+	}
+
+	goMod, err := tidy.Apply().File("go.mod").Contents(ctx)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(goMod, "go.opentelemetry.io/otel") {
+		return errors.New("expected go.mod to require go.opentelemetry.io/otel")
+	}
+
+	return nil
+}
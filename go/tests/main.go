@@ -28,7 +28,17 @@ func (m *GoTests) All(ctx context.Context) error {
 
 	ep.Go(m.Build().All)
 	ep.Go(m.Test().All)
+	ep.Go(m.Coverage().All)
 	ep.Go(m.Library().All)
+	ep.Go(m.Release().All)
+	ep.Go(m.Sbom().All)
+	ep.Go(m.Sign().All)
+	ep.Go(m.Image().All)
+	ep.Go(m.Publish().All)
+	ep.Go(m.Workspace().All)
+	ep.Go(m.Affected().All)
+	ep.Go(m.Vulncheck().All)
+	ep.Go(m.Format().All)
 
 	return ep.Wait()
 }
@@ -0,0 +1,169 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"dagger/gotests/internal/dagger"
+
+	"github.com/sourcegraph/conc/pool"
+)
+
+type Publish struct {
+	// +private
+	Go *dagger.Go
+}
+
+func (m *GoTests) Publish() *Publish {
+	return &Publish{
+		Go: m.Go,
+	}
+}
+
+func (p *Publish) All(ctx context.Context) error {
+	ep := pool.New().WithErrors().WithContext(ctx)
+
+	ep.Go(p.BuildOrderTest)
+	ep.Go(p.PublishOrderTest)
+	ep.Go(p.PublishPartialFailureTest)
+
+	return ep.Wait()
+}
+
+func (p *Publish) application(registry *dagger.Service) *dagger.GoApplication {
+	return p.Go.Module(dag.CurrentModule().Source().Directory("testdata/buildoutput")).
+		Library().
+		Application(".").
+		WithRegistryService(registry)
+}
+
+// variants builds the application for the platforms, in the given order.
+func variants(ctx context.Context, app *dagger.GoApplication, platforms []dagger.Platform) ([]*dagger.Container, error) {
+	built, err := app.Build(ctx, dagger.GoApplicationBuildOpts{
+		Platforms: platforms,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctrs := make([]*dagger.Container, len(built))
+	for i := range built {
+		ctrs[i] = &built[i]
+	}
+
+	return ctrs, nil
+}
+
+func (p *Publish) BuildOrderTest(ctx context.Context) error {
+	platforms := []dagger.Platform{"linux/arm64", "linux/386", "linux/amd64"}
+
+	ctrs, err := variants(ctx, p.application(registryService()), platforms)
+	if err != nil {
+		return err
+	}
+
+	built := make([]dagger.Platform, len(ctrs))
+	for i, ctr := range ctrs {
+		built[i], err = ctr.Platform(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !slices.Equal(built, platforms) {
+		return fmt.Errorf("expected variants in platform order: %v != %v", built, platforms)
+	}
+
+	return nil
+}
+
+func (p *Publish) PublishOrderTest(ctx context.Context) error {
+	app := p.application(registryService())
+
+	platforms := []dagger.Platform{"linux/arm64", "linux/amd64"}
+
+	ctrs, err := variants(ctx, app, platforms)
+	if err != nil {
+		return err
+	}
+
+	tags := []string{"v1.0.0", "v1.0", "v1", "latest"}
+
+	results, err := app.Publish(ctx, "registry:5000", "ordered", tags, ctrs, "", dag.SetSecret("registry-password", ""))
+	if err != nil {
+		return err
+	}
+
+	if len(results) != len(tags) {
+		return fmt.Errorf("expected a result per tag: %d != %d", len(results), len(tags))
+	}
+
+	var digests []string
+	for i, result := range results {
+		tag, err := result.Tag(ctx)
+		if err != nil {
+			return err
+		}
+		if tag != tags[i] {
+			return fmt.Errorf("expected results in tag order: %s != %s", tag, tags[i])
+		}
+
+		published, err := result.Platforms(ctx)
+		if err != nil {
+			return err
+		}
+		if !slices.Equal(published, platforms) {
+			return fmt.Errorf("expected platforms in variant order: %v != %v", published, platforms)
+		}
+
+		digest, err := result.Digest(ctx)
+		if err != nil {
+			return err
+		}
+		digests = append(digests, digest)
+	}
+
+	if len(slices.Compact(digests)) != 1 {
+		return fmt.Errorf("expected every tag to refer to the same image index: %v", digests)
+	}
+
+	return nil
+}
+
+// A failed tag must not stop the remaining tags from being pushed.
+func (p *Publish) PublishPartialFailureTest(ctx context.Context) error {
+	registry := registryService()
+	app := p.application(registry)
+
+	ctrs, err := variants(ctx, app, []dagger.Platform{"linux/amd64"})
+	if err != nil {
+		return err
+	}
+
+	_, err = app.Publish(ctx, "registry:5000", "partial", []string{"invalid tag", "good"}, ctrs, "", dag.SetSecret("registry-password", ""))
+	if err == nil {
+		return errors.New("expected publishing an invalid tag to fail")
+	}
+	if !strings.Contains(err.Error(), "invalid tag") {
+		return fmt.Errorf("expected error to identify the failed tag: %w", err)
+	}
+
+	_, err = dag.Container().
+		From("ghcr.io/oras-project/oras:v1.2.3").
+		WithServiceBinding("registry", registry).
+		WithExec([]string{"oras", "manifest", "fetch", "--plain-http", "registry:5000/partial:good"}).
+		Sync(ctx)
+	if err != nil {
+		return fmt.Errorf("expected the valid tag to be published: %w", err)
+	}
+
+	return nil
+}
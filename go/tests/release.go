@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"dagger/gotests/internal/dagger"
+
+	"github.com/sourcegraph/conc/pool"
+)
+
+type Release struct {
+	// +private
+	Go *dagger.Go
+}
+
+func (m *GoTests) Release() *Release {
+	return &Release{
+		Go: m.Go,
+	}
+}
+
+func (r *Release) All(ctx context.Context) error {
+	ep := pool.New().WithErrors().WithContext(ctx)
+
+	ep.Go(r.ArchivesTest)
+	ep.Go(r.ArchiveContentsTest)
+
+	return ep.Wait()
+}
+
+func (r *Release) release() *dagger.Directory {
+	return r.Go.Module(dag.CurrentModule().Source().Directory("testdata/buildoutput")).
+		Library().
+		Application(".").
+		Release(dagger.GoApplicationReleaseOpts{
+			Version:   "v1.2.3",
+			Platforms: []dagger.Platform{"linux/amd64", "windows/amd64"},
+			Include: []*dagger.File{
+				dag.File("LICENSE", "MIT"),
+			},
+		})
+}
+
+func (r *Release) ArchivesTest(ctx context.Context) error {
+	dist := r.release()
+
+	entries, err := dist.Entries(ctx)
+	if err != nil {
+		return err
+	}
+
+	expected := []string{
+		"buildoutput_1.2.3_linux_amd64.tar.gz",
+		"buildoutput_1.2.3_windows_amd64.zip",
+		"checksums.txt",
+	}
+
+	slices.Sort(entries)
+	if !slices.Equal(entries, expected) {
+		return fmt.Errorf("unexpected release artifacts: %v", entries)
+	}
+
+	checksums, err := dist.File("checksums.txt").Contents(ctx)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(strings.TrimSpace(checksums), "\n")
+	if len(lines) != 2 {
+		return fmt.Errorf("expected a checksum for each archive: %v", lines)
+	}
+
+	for i, line := range lines {
+		sum, file, ok := strings.Cut(line, "  ")
+		if !ok || len(sum) != 64 || file != expected[i] {
+			return errors.New("malformed checksum line: " + line)
+		}
+	}
+
+	_, err = dag.Container().
+		From("alpine:3.22").
+		WithMountedDirectory("/dist", dist).
+		WithWorkdir("/dist").
+		WithExec([]string{"sha256sum", "-c", "checksums.txt"}).
+		Sync(ctx)
+	return err
+}
+
+func (r *Release) ArchiveContentsTest(ctx context.Context) error {
+	dist := r.release()
+
+	dir := dag.Archive().Zip().Extract(dist.File("buildoutput_1.2.3_windows_amd64.zip"))
+
+	entries, err := dir.Entries(ctx)
+	if err != nil {
+		return err
+	}
+
+	slices.Sort(entries)
+	if !slices.Equal(entries, []string{"LICENSE", "buildoutput.exe"}) {
+		return fmt.Errorf("unexpected archive contents: %v", entries)
+	}
+
+	return nil
+}
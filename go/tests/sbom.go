@@ -0,0 +1,116 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"dagger/gotests/internal/dagger"
+
+	"github.com/sourcegraph/conc/pool"
+)
+
+type Sbom struct {
+	// +private
+	Go *dagger.Go
+}
+
+func (m *GoTests) Sbom() *Sbom {
+	return &Sbom{
+		Go: m.Go,
+	}
+}
+
+func (s *Sbom) All(ctx context.Context) error {
+	ep := pool.New().WithErrors().WithContext(ctx)
+
+	ep.Go(s.LibraryCyclonedxTest)
+	ep.Go(s.LibrarySpdxTest)
+	ep.Go(s.ApplicationTest)
+
+	return ep.Wait()
+}
+
+func (s *Sbom) library() *dagger.GoLibrary {
+	return s.Go.Module(dag.CurrentModule().Source().Directory("testdata/library/vulnerable-dependency")).
+		Library()
+}
+
+func (s *Sbom) LibraryCyclonedxTest(ctx context.Context) error {
+	sbom, err := s.library().Sbom().Contents(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(sbom, "CycloneDX") {
+		return errors.New("expected a CycloneDX sbom")
+	}
+	if !strings.Contains(sbom, "golang.org/x/text") {
+		return errors.New("expected sbom to list the golang.org/x/text dependency")
+	}
+
+	return nil
+}
+
+func (s *Sbom) LibrarySpdxTest(ctx context.Context) error {
+	sbom, err := s.library().Sbom(dagger.GoLibrarySbomOpts{
+		Format: dagger.GoSbomFormatSpdx,
+	}).Contents(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(sbom, "spdxVersion") {
+		return errors.New("expected an SPDX sbom")
+	}
+	if !strings.Contains(sbom, "golang.org/x/text") {
+		return errors.New("expected sbom to list the golang.org/x/text dependency")
+	}
+
+	return nil
+}
+
+func (s *Sbom) ApplicationTest(ctx context.Context) error {
+	app := s.Go.Module(dag.CurrentModule().Source().Directory("testdata/buildoutput")).
+		Library().
+		Application(".")
+
+	variants, err := app.Build(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctrs := make([]*dagger.Container, len(variants))
+	for i := range variants {
+		ctrs[i] = &variants[i]
+	}
+
+	sboms, err := app.Sbom(ctx, ctrs)
+	if err != nil {
+		return err
+	}
+
+	if len(sboms) != len(ctrs) {
+		return fmt.Errorf("expected an sbom per platform variant: %d != %d", len(sboms), len(ctrs))
+	}
+
+	for _, sbom := range sboms {
+		contents, err := sbom.Contents(ctx)
+		if err != nil {
+			return err
+		}
+
+		// Only the Go binary is in the image so its build info must be the source.
+		if !strings.Contains(contents, "stdlib") {
+			return errors.New("expected sbom to list the Go standard library from the binary build info")
+		}
+	}
+
+	return nil
+}
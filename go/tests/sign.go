@@ -0,0 +1,152 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"dagger/gotests/internal/dagger"
+
+	"github.com/sourcegraph/conc/pool"
+)
+
+type Sign struct {
+	// +private
+	Go *dagger.Go
+}
+
+func (m *GoTests) Sign() *Sign {
+	return &Sign{
+		Go: m.Go,
+	}
+}
+
+func (s *Sign) All(ctx context.Context) error {
+	ep := pool.New().WithErrors().WithContext(ctx)
+
+	ep.Go(s.PublishSignedTest)
+	ep.Go(s.VerifyWrongKeyTest)
+
+	return ep.Wait()
+}
+
+func registryService() *dagger.Service {
+	return dag.Container().
+		From("registry:2").
+		WithExposedPort(5000).
+		AsService()
+}
+
+// keyPair generates an unencrypted cosign key pair, returning the
+// private and public key. Differently named key pairs are never the
+// same since the name is part of the cache key of the generation.
+func keyPair(ctx context.Context, name string) (*dagger.Secret, *dagger.File, error) {
+	keys := dag.Container().
+		From("ghcr.io/sigstore/cosign/cosign:v2.4.1").
+		WithEnvVariable("COSIGN_PASSWORD", "").
+		WithEnvVariable("KEY_NAME", name).
+		WithWorkdir("/keys").
+		WithExec([]string{"cosign", "generate-key-pair"}).
+		Directory("/keys")
+
+	privateKey, err := keys.File("cosign.key").Contents(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dag.SetSecret("cosign-key-"+name, privateKey), keys.File("cosign.pub"), nil
+}
+
+// publishSigned publishes the application to a local registry with a
+// signature and provenance attestation, returning the published reference.
+func (s *Sign) publishSigned(ctx context.Context, app *dagger.GoApplication, imageName string, key *dagger.Secret) (string, error) {
+	variants, err := app.Build(ctx, dagger.GoApplicationBuildOpts{
+		Platforms: []dagger.Platform{"linux/amd64"},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	ctrs := make([]*dagger.Container, len(variants))
+	for i := range variants {
+		ctrs[i] = &variants[i]
+	}
+
+	results, err := app.
+		WithSigningKey(key).
+		Publish(ctx, "registry:5000", imageName, []string{"latest"}, ctrs, "", dag.SetSecret("registry-password", ""), dagger.GoApplicationPublishOpts{
+			Provenance: app.Provenance(dagger.GoApplicationProvenanceOpts{
+				Platforms: []dagger.Platform{"linux/amd64"},
+			}),
+		})
+	if err != nil {
+		return "", err
+	}
+
+	if len(results) != 1 {
+		return "", fmt.Errorf("expected a single publish result: %d", len(results))
+	}
+
+	return results[0].String(ctx)
+}
+
+func (s *Sign) application() *dagger.GoApplication {
+	return s.Go.Module(dag.CurrentModule().Source().Directory("testdata/buildoutput")).
+		Library().
+		Application(".").
+		WithRegistryService(registryService())
+}
+
+func (s *Sign) PublishSignedTest(ctx context.Context) error {
+	key, publicKey, err := keyPair(ctx, "signed")
+	if err != nil {
+		return err
+	}
+
+	app := s.application()
+
+	ref, err := s.publishSigned(ctx, app, "signed", key)
+	if err != nil {
+		return err
+	}
+
+	err = app.Verify(ctx, ref, publicKey, dagger.GoApplicationVerifyOpts{
+		Provenance: true,
+	})
+	if err != nil {
+		return fmt.Errorf("expected signature and provenance of %s to verify: %w", ref, err)
+	}
+
+	return nil
+}
+
+func (s *Sign) VerifyWrongKeyTest(ctx context.Context) error {
+	key, _, err := keyPair(ctx, "wrong-key")
+	if err != nil {
+		return err
+	}
+
+	_, otherPublicKey, err := keyPair(ctx, "other")
+	if err != nil {
+		return err
+	}
+
+	app := s.application()
+
+	ref, err := s.publishSigned(ctx, app, "wrong-key", key)
+	if err != nil {
+		return err
+	}
+
+	err = app.Verify(ctx, ref, otherPublicKey)
+	if err == nil {
+		return errors.New("expected verification with a different public key to fail")
+	}
+
+	return nil
+}
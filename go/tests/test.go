@@ -7,6 +7,7 @@ package main
 
 import (
 	"context"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"strings"
@@ -32,6 +33,9 @@ func (t *Test) All(ctx context.Context) error {
 	ep := pool.New().WithErrors().WithContext(ctx)
 
 	ep.Go(t.CoverageTest)
+	ep.Go(t.CoverageFailureTest)
+	ep.Go(t.JunitTest)
+	ep.Go(t.SummaryTest)
 
 	return ep.Wait()
 }
@@ -68,3 +72,111 @@ func (t *Test) CoverageTest(ctx context.Context) error {
 
 	return nil
 }
+
+func (t *Test) CoverageFailureTest(ctx context.Context) error {
+	_, err := t.Go.Module(dag.CurrentModule().Source().Directory("testdata/testreport")).
+		Test("./...").
+		Coverage().
+		Sync(ctx)
+	if err == nil {
+		return errors.New("expected coverage to fail due to failing test")
+	}
+
+	if !strings.Contains(err.Error(), "--- FAIL: testreport.TestFail") {
+		return errors.New("expected error to report failing test: " + err.Error())
+	}
+
+	return nil
+}
+
+func (t *Test) JunitTest(ctx context.Context) error {
+	contents, err := t.Go.Module(dag.CurrentModule().Source().Directory("testdata/testreport")).
+		Test("./...").
+		Junit().
+		Contents(ctx)
+	if err != nil {
+		return err
+	}
+
+	var report struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Skipped  int `xml:"skipped,attr"`
+		Suites   []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"testsuite"`
+	}
+	err = xml.Unmarshal([]byte(contents), &report)
+	if err != nil {
+		return err
+	}
+
+	if report.Tests != 3 || report.Failures != 1 || report.Skipped != 1 {
+		return fmt.Errorf(
+			"unexpected junit totals (tests=%d, failures=%d, skipped=%d)",
+			report.Tests,
+			report.Failures,
+			report.Skipped,
+		)
+	}
+
+	if len(report.Suites) != 1 || report.Suites[0].Name != "testreport" {
+		return fmt.Errorf("expected a single testsuite for package testreport: %v", report.Suites)
+	}
+
+	return nil
+}
+
+func (t *Test) SummaryTest(ctx context.Context) error {
+	summary := t.Go.Module(dag.CurrentModule().Source().Directory("testdata/testreport")).
+		Test("./...").
+		Summary()
+
+	passed, err := summary.Passed(ctx)
+	if err != nil {
+		return err
+	}
+
+	failed, err := summary.Failed(ctx)
+	if err != nil {
+		return err
+	}
+
+	skipped, err := summary.Skipped(ctx)
+	if err != nil {
+		return err
+	}
+
+	if passed != 1 || failed != 1 || skipped != 1 {
+		return fmt.Errorf("unexpected summary (passed=%d, failed=%d, skipped=%d)", passed, failed, skipped)
+	}
+
+	failures, err := summary.Failures(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(failures) != 1 {
+		return fmt.Errorf("expected only 1 failing test but received: %d", len(failures))
+	}
+
+	name, err := failures[0].Name(ctx)
+	if err != nil {
+		return err
+	}
+
+	if name != "TestFail" {
+		return errors.New("unexpected failing test: " + name)
+	}
+
+	output, err := failures[0].Output(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(output, "this test always fails") {
+		return errors.New("expected failing test output to be captured: " + output)
+	}
+
+	return nil
+}
@@ -0,0 +1,14 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package coverage
+
+import "testing"
+
+func TestCovered(t *testing.T) {
+	if s := Covered(); s != "covered" {
+		t.Errorf("unexpected value: %s", s)
+	}
+}
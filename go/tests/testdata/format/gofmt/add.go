@@ -0,0 +1,5 @@
+package gofmt
+
+func Add(a int,b int) int {
+  return a+b
+}
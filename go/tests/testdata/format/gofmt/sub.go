@@ -0,0 +1,6 @@
+package gofmt
+
+// Sub returns the difference of a and b.
+func Sub(a, b int) int {
+	return a - b
+}
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"example.com/strict/internal/greeting"
+	"fmt"
+)
+
+func main() {
+
+	fmt.Println(greeting.Hello("world"))
+}
@@ -0,0 +1,7 @@
+package vulnerable
+
+import "golang.org/x/text/language"
+
+func Parse(s string) (language.Tag, error) {
+	return language.Parse(s)
+}
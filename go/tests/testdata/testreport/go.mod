@@ -0,0 +1,3 @@
+module testreport
+
+go 1.24.0
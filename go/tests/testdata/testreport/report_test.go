@@ -0,0 +1,22 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package testreport
+
+import "testing"
+
+func TestPass(t *testing.T) {
+	if s := HelloWorld(); s != "hello world" {
+		t.Errorf("unexpected greeting: %s", s)
+	}
+}
+
+func TestFail(t *testing.T) {
+	t.Error("this test always fails")
+}
+
+func TestSkip(t *testing.T) {
+	t.Skip("this test is always skipped")
+}
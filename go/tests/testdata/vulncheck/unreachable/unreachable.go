@@ -0,0 +1,12 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// Package unreachable imports a vulnerable package without calling any
+// of its vulnerable functions.
+package unreachable
+
+import "golang.org/x/text/language"
+
+var Default = language.English
@@ -0,0 +1,12 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package app
+
+import "example.com/lib/strs"
+
+func Greet(name string) string {
+	return strs.Shout("hello " + name)
+}
@@ -0,0 +1,18 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package strs
+
+import (
+	_ "embed"
+	"strings"
+)
+
+//go:embed templates/suffix.txt
+var suffix string
+
+func Shout(s string) string {
+	return strings.ToUpper(s) + suffix
+}
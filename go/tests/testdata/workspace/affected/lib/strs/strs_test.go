@@ -0,0 +1,14 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package strs
+
+import "testing"
+
+func TestShout(t *testing.T) {
+	if Shout("hi") != "HI!" {
+		t.Fail()
+	}
+}
@@ -0,0 +1,10 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package a
+
+func Name() string {
+	return "a"
+}
@@ -0,0 +1,14 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package nested
+
+import "testing"
+
+func TestName(t *testing.T) {
+	if Name() != "nested" {
+		t.Fail()
+	}
+}
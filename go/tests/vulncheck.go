@@ -0,0 +1,151 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"dagger/gotests/internal/dagger"
+
+	"github.com/sourcegraph/conc/pool"
+)
+
+// A vulnerability in golang.org/x/text/language.Parse which the
+// testdata modules depend on through golang.org/x/text v0.3.0.
+const parseVulnerability = "GO-2021-0113"
+
+type Vulncheck struct {
+	// +private
+	Go *dagger.Go
+}
+
+func (m *GoTests) Vulncheck() *Vulncheck {
+	return &Vulncheck{
+		Go: m.Go,
+	}
+}
+
+func (v *Vulncheck) All(ctx context.Context) error {
+	ep := pool.New().WithErrors().WithContext(ctx)
+
+	ep.Go(v.ReachableTest)
+	ep.Go(v.UnreachableTest)
+	ep.Go(v.BinaryTest)
+	ep.Go(v.SarifTest)
+	ep.Go(v.InstallTest)
+
+	return ep.Wait()
+}
+
+// vulncheck uses a local copy of the vulnerability database and a
+// prebuilt govulncheck so the checks themselves need no network access.
+func (v *Vulncheck) vulncheck(path string) *dagger.GoVulncheck {
+	return v.Go.Module(dag.CurrentModule().Source().Directory(path)).
+		Vulncheck(dagger.GoModVulncheckOpts{
+			Db:     v.Go.VulnDb(),
+			Binary: v.Go.Govulncheck(),
+		})
+}
+
+func findReachable(ctx context.Context, vulncheck *dagger.GoVulncheck, report *dagger.File, id string) (bool, error) {
+	findings, err := vulncheck.Findings(ctx, report)
+	if err != nil {
+		return false, err
+	}
+
+	for _, finding := range findings {
+		findingID, err := finding.ID(ctx)
+		if err != nil {
+			return false, err
+		}
+		if findingID != id {
+			continue
+		}
+
+		return finding.Reachable(ctx)
+	}
+
+	return false, fmt.Errorf("expected a finding for %s", id)
+}
+
+func (v *Vulncheck) ReachableTest(ctx context.Context) error {
+	vulncheck := v.vulncheck("testdata/library/vulnerable-dependency")
+
+	reachable, err := findReachable(ctx, vulncheck, vulncheck.Source(), parseVulnerability)
+	if err != nil {
+		return err
+	}
+	if !reachable {
+		return fmt.Errorf("expected %s to be reachable through language.Parse", parseVulnerability)
+	}
+
+	err = vulncheck.Check(ctx)
+	if err == nil {
+		return errors.New("expected check to fail on a reachable vulnerability")
+	}
+
+	return nil
+}
+
+func (v *Vulncheck) UnreachableTest(ctx context.Context) error {
+	vulncheck := v.vulncheck("testdata/vulncheck/unreachable")
+
+	reachable, err := findReachable(ctx, vulncheck, vulncheck.Source(), parseVulnerability)
+	if err != nil {
+		return err
+	}
+	if reachable {
+		return fmt.Errorf("expected %s to be unreachable", parseVulnerability)
+	}
+
+	return vulncheck.Check(ctx)
+}
+
+func (v *Vulncheck) BinaryTest(ctx context.Context) error {
+	mod := v.Go.Module(dag.CurrentModule().Source().Directory("testdata/vulncheck/binary"))
+	vulncheck := v.vulncheck("testdata/vulncheck/binary")
+
+	report := vulncheck.Binary(mod.Build(".").Output())
+
+	reachable, err := findReachable(ctx, vulncheck, report, parseVulnerability)
+	if err != nil {
+		return err
+	}
+	if !reachable {
+		return fmt.Errorf("expected %s to be reachable within the binary", parseVulnerability)
+	}
+
+	return nil
+}
+
+func (v *Vulncheck) SarifTest(ctx context.Context) error {
+	report, err := v.vulncheck("testdata/library/vulnerable-dependency").
+		Source(dagger.GoVulncheckSourceOpts{
+			Format: dagger.GoVulncheckFormatSarif,
+		}).
+		Contents(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(report, parseVulnerability) {
+		return fmt.Errorf("expected sarif report to contain %s", parseVulnerability)
+	}
+
+	return nil
+}
+
+// Without a prebuilt binary the pinned version of govulncheck is installed.
+func (v *Vulncheck) InstallTest(ctx context.Context) error {
+	return v.Go.Module(dag.CurrentModule().Source().Directory("testdata/vulncheck/unreachable")).
+		Vulncheck(dagger.GoModVulncheckOpts{
+			Db: v.Go.VulnDb(),
+		}).
+		Check(ctx)
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"dagger/gotests/internal/dagger"
+
+	"github.com/sourcegraph/conc/pool"
+)
+
+type Workspace struct {
+	// +private
+	Go *dagger.Go
+}
+
+func (m *GoTests) Workspace() *Workspace {
+	return &Workspace{
+		Go: m.Go,
+	}
+}
+
+func (w *Workspace) All(ctx context.Context) error {
+	ep := pool.New().WithErrors().WithContext(ctx)
+
+	ep.Go(w.GoWorkPathsTest)
+	ep.Go(w.DiscoverPathsTest)
+	ep.Go(w.CiTest)
+	ep.Go(w.CiFailureTest)
+
+	return ep.Wait()
+}
+
+func (w *Workspace) workspace(name string) *dagger.GoWorkspace {
+	return w.Go.Workspace(dag.CurrentModule().Source().Directory("testdata/workspace/" + name))
+}
+
+func (w *Workspace) ci(name string) *dagger.GoWorkspaceCiReport {
+	return w.workspace(name).Ci(dagger.GoWorkspaceCiOpts{
+		Linter:         dag.Noop().GoLinter().AsGoLinter(),
+		StaticAnalyzer: dag.Noop().GoStaticAnalyzer().AsGoStaticAnalyzer(),
+	})
+}
+
+func (w *Workspace) GoWorkPathsTest(ctx context.Context) error {
+	paths, err := w.workspace("work").Paths(ctx)
+	if err != nil {
+		return err
+	}
+
+	// c has a go.mod but is not used by go.work.
+	expected := []string{"a", "b"}
+	if !slices.Equal(paths, expected) {
+		return fmt.Errorf("expected modules used by go.work: %v != %v", paths, expected)
+	}
+
+	return nil
+}
+
+func (w *Workspace) DiscoverPathsTest(ctx context.Context) error {
+	paths, err := w.workspace("monorepo").Paths(ctx)
+	if err != nil {
+		return err
+	}
+
+	expected := []string{"a", "b/nested", "failing"}
+	if !slices.Equal(paths, expected) {
+		return fmt.Errorf("expected every module outside of testdata: %v != %v", paths, expected)
+	}
+
+	return nil
+}
+
+func (w *Workspace) CiTest(ctx context.Context) error {
+	report := w.ci("work")
+
+	passed, err := report.Passed(ctx)
+	if err != nil {
+		return err
+	}
+	if !passed {
+		s, err := report.String(ctx)
+		if err != nil {
+			return err
+		}
+
+		return errors.New("expected ci to pass for every module:\n" + s)
+	}
+
+	return report.Check(ctx)
+}
+
+func (w *Workspace) CiFailureTest(ctx context.Context) error {
+	report := w.ci("monorepo")
+
+	modules, err := report.Modules(ctx)
+	if err != nil {
+		return err
+	}
+
+	results := make(map[string]bool, len(modules))
+	for _, module := range modules {
+		path, err := module.Path(ctx)
+		if err != nil {
+			return err
+		}
+
+		passed, err := module.Passed(ctx)
+		if err != nil {
+			return err
+		}
+
+		results[path] = passed
+	}
+
+	expected := map[string]bool{
+		"a":        true,
+		"b/nested": true,
+		"failing":  false,
+	}
+	for path, passed := range expected {
+		if results[path] != passed {
+			return fmt.Errorf("expected ci of %s to have passed=%t: %v", path, passed, results)
+		}
+	}
+
+	err = report.Check(ctx)
+	if err == nil {
+		return errors.New("expected check to fail for the failing module")
+	}
+
+	return nil
+}
@@ -62,3 +62,19 @@ func (t *Tidy) Diff(ctx context.Context) ([]string, error) {
 	changes := slices.Collect(strings.Lines(stdout))
 	return changes, nil
 }
+
+// Return the module directory after updating go.mod and go.sum, e.g. to
+// export over the local copy of the module.
+func (t *Tidy) Apply(
+	// Only return go.mod and go.sum if they were changed.
+	// +optional
+	changedOnly bool,
+) *dagger.Directory {
+	before := t.Ctr.Directory(".")
+	after := t.Ctr.WithExec([]string{"go", "mod", "tidy"}).Directory(".")
+	if changedOnly {
+		return before.Diff(after)
+	}
+
+	return after
+}
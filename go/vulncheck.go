@@ -0,0 +1,274 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"dagger/go/internal/dagger"
+	"dagger/go/internal/govulncheck"
+)
+
+type VulncheckFormat string
+
+const (
+	Json  VulncheckFormat = "json"
+	Sarif VulncheckFormat = "sarif"
+	Text  VulncheckFormat = "text"
+)
+
+// govulncheckVersion pins the version of govulncheck installed when no
+// version or prebuilt binary is given.
+const govulncheckVersion = "v1.1.4"
+
+type Vulncheck struct {
+	// +private
+	Ctr *dagger.Container
+
+	// +private
+	Args []string
+}
+
+// Check for known vulnerabilities in code reachable from a Go module
+// or within binaries built from it using govulncheck.
+func (m *Mod) Vulncheck(
+	// A local copy of the Go vulnerability database, e.g. as returned by
+	// VulnDb, so no network access is needed to look up vulnerabilities.
+	// +optional
+	db *dagger.Directory,
+
+	// Version of govulncheck to install, defaults to a pinned version.
+	// +optional
+	version string,
+
+	// A prebuilt govulncheck binary, e.g. as returned by Govulncheck, used
+	// instead of installing govulncheck. Along with db, no network access
+	// is needed at all.
+	// +optional
+	binary *dagger.File,
+) *Vulncheck {
+	ctr := m.Ctr
+	if binary != nil {
+		ctr = ctr.WithMountedFile("/usr/local/bin/govulncheck", binary)
+	} else {
+		ctr = ctr.WithExec([]string{"go", "install", govulncheckPackage(version)})
+	}
+
+	var args []string
+	if db != nil {
+		ctr = ctr.WithMountedDirectory("/vulndb", db)
+		args = append(args, "-db", "file:///vulndb")
+	}
+
+	return &Vulncheck{
+		Ctr:  ctr,
+		Args: args,
+	}
+}
+
+// Download the Go vulnerability database so it can be mirrored to
+// environments without internet access.
+func (m *Go) VulnDb() *dagger.Directory {
+	return dag.Archive().Extract(dag.HTTP("https://vuln.go.dev/vulndb.zip"))
+}
+
+// Build govulncheck so it can be mirrored to environments without
+// internet access and passed to Vulncheck.
+func (m *Go) Govulncheck(
+	// Version of govulncheck to build, defaults to a pinned version.
+	// +optional
+	version string,
+) *dagger.File {
+	return m.Container.
+		WithEnvVariable("CGO_ENABLED", "0").
+		WithEnvVariable("GOBIN", "/tmp/bin").
+		WithExec([]string{"go", "install", govulncheckPackage(version)}).
+		File("/tmp/bin/govulncheck")
+}
+
+func govulncheckPackage(version string) string {
+	if version == "" {
+		version = govulncheckVersion
+	}
+	return "golang.org/x/vuln/cmd/govulncheck@" + version
+}
+
+// run returns the report written by govulncheck. Text reports exit with
+// 3 when vulnerabilities are found, which is not treated as a failure.
+func (v *Vulncheck) run(ctx context.Context, ctr *dagger.Container, format VulncheckFormat, args ...string) (*dagger.File, error) {
+	output := "/tmp/govulncheck." + string(format)
+	if format == Text {
+		output = "/tmp/govulncheck.txt"
+	}
+
+	cmd := []string{"govulncheck", "-format", string(format)}
+	cmd = append(cmd, v.Args...)
+	cmd = append(cmd, args...)
+
+	ctr = ctr.WithExec(cmd, dagger.ContainerWithExecOpts{
+		RedirectStdout: output,
+		Expect:         dagger.ReturnTypeAny,
+	})
+
+	code, err := ctr.ExitCode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if code != 0 && code != 3 {
+		stderr, err := ctr.Stderr(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("govulncheck exited with %d: %s", code, stderr)
+	}
+
+	return ctr.File(output), nil
+}
+
+// Check the source code of packages for vulnerabilities and return the report.
+func (v *Vulncheck) Source(
+	ctx context.Context,
+
+	// +default="./..."
+	pkg string,
+
+	// +default="json"
+	format VulncheckFormat,
+) (*dagger.File, error) {
+	return v.run(ctx, v.Ctr, format, strings.Fields(pkg)...)
+}
+
+// Check a binary built from the module, e.g. the output of Build, for
+// vulnerabilities and return the report.
+func (v *Vulncheck) Binary(
+	ctx context.Context,
+
+	binary *dagger.File,
+
+	// +default="json"
+	format VulncheckFormat,
+) (*dagger.File, error) {
+	name, err := binary.Name(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filename := path.Join("/tmp/bin", path.Base(name))
+
+	ctr := v.Ctr.WithMountedFile(filename, binary)
+
+	return v.run(ctx, ctr, format, "-mode", "binary", filename)
+}
+
+// VulnFinding is a vulnerability found by govulncheck, at the most precise
+// level it was found: a required module, an imported package or a reachable
+// function.
+type VulnFinding struct {
+	// Identifier of the vulnerability, e.g. GO-2021-0113.
+	ID string
+
+	Summary string
+
+	// Vulnerable module and the version which is required.
+	Module  string
+	Version string
+
+	// Version of the module fixing the vulnerability, if any.
+	FixedVersion string
+
+	// Vulnerable package, if it is imported.
+	Package string
+
+	// Vulnerable function, if it is reachable.
+	Function string
+
+	// Whether the vulnerable function is reachable from the code.
+	Reachable bool
+}
+
+// Format the finding as a single line.
+func (f *VulnFinding) String() string {
+	location := f.Module + "@" + f.Version
+	if f.Function != "" {
+		location = f.Package + "." + f.Function
+	}
+
+	fixed := "no fix available"
+	if f.FixedVersion != "" {
+		fixed = "fixed in " + f.FixedVersion
+	}
+
+	return fmt.Sprintf("%s: %s (%s, %s)", f.ID, f.Summary, location, fixed)
+}
+
+// Parse a JSON report returned by Source or Binary into a finding per vulnerability.
+func (v *Vulncheck) Findings(ctx context.Context, report *dagger.File) ([]*VulnFinding, error) {
+	contents, err := report.Contents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	findings, err := govulncheck.Parse(strings.NewReader(contents))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*VulnFinding, len(findings))
+	for i, f := range findings {
+		result[i] = &VulnFinding{
+			ID:           f.ID,
+			Summary:      f.Summary,
+			Module:       f.Module,
+			Version:      f.Version,
+			FixedVersion: f.FixedVersion,
+			Package:      f.Package,
+			Function:     f.Function,
+			Reachable:    f.Level == govulncheck.Symbol,
+		}
+	}
+
+	return result, nil
+}
+
+// Validate no vulnerable function is reachable from the packages. Vulnerable
+// modules or packages whose vulnerable code is never called are ignored.
+func (v *Vulncheck) Check(
+	ctx context.Context,
+
+	// +default="./..."
+	pkg string,
+) error {
+	report, err := v.Source(ctx, pkg, Json)
+	if err != nil {
+		return err
+	}
+
+	findings, err := v.Findings(ctx, report)
+	if err != nil {
+		return err
+	}
+
+	var reachable []string
+	for _, f := range findings {
+		if f.Reachable {
+			reachable = append(reachable, f.String())
+		}
+	}
+
+	if len(reachable) > 0 {
+		return fmt.Errorf(
+			"found %d reachable vulnerabilities:\n%s",
+			len(reachable),
+			strings.Join(reachable, "\n"),
+		)
+	}
+
+	return nil
+}
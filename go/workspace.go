@@ -0,0 +1,369 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"dagger/go/internal/dagger"
+
+	"github.com/sourcegraph/conc/pool"
+)
+
+// Workspace
+type Workspace struct {
+	// +private
+	Go *Go
+
+	// +private
+	Source *dagger.Directory
+
+	// +private
+	CoverageThreshold float64
+
+	// +private
+	PackageCoverageThreshold float64
+
+	// +private
+	LintSeverity LintSeverity
+
+	// +private
+	VulncheckEnabled bool
+
+	// +private
+	VulnDb *dagger.Directory
+
+	// +private
+	VulncheckVersion string
+
+	// +private
+	VulncheckBinary *dagger.File
+
+	// +private
+	Formatter Formatter
+
+	// +private
+	LocalPrefix string
+
+	// +private
+	FormatterVersion string
+}
+
+// Mount a directory containing multiple Go modules, either listed by
+// a go.work file or discovered from every go.mod in the directory.
+func (m *Go) Workspace(source *dagger.Directory) *Workspace {
+	return &Workspace{
+		Go:     m,
+		Source: source,
+	}
+}
+
+// Paths of the modules in the workspace, relative to the source directory.
+func (w *Workspace) Paths(ctx context.Context) ([]string, error) {
+	entries, err := w.Source.Entries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if slices.Contains(entries, "go.work") {
+		return w.workPaths(ctx)
+	}
+
+	return w.discoverPaths(ctx)
+}
+
+// workPaths reads the module paths from the use directives of go.work.
+func (w *Workspace) workPaths(ctx context.Context) ([]string, error) {
+	out, err := w.Go.Container.
+		WithMountedDirectory("/src", w.Source).
+		WithWorkdir("/src").
+		WithExec([]string{"go", "work", "edit", "-json"}).
+		Stdout(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var work struct {
+		Use []struct {
+			DiskPath string
+		}
+	}
+	err = json.Unmarshal([]byte(out), &work)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(work.Use))
+	for _, use := range work.Use {
+		p := path.Clean(use.DiskPath)
+		if path.IsAbs(p) || p == ".." || strings.HasPrefix(p, "../") {
+			return nil, errors.New("go.work uses a module outside of the workspace: " + use.DiskPath)
+		}
+
+		paths = append(paths, p)
+	}
+
+	slices.Sort(paths)
+	return slices.Compact(paths), nil
+}
+
+// discoverPaths finds every go.mod, skipping vendored and test data
+// modules which the go command would ignore as well.
+func (w *Workspace) discoverPaths(ctx context.Context) ([]string, error) {
+	files, err := w.Source.Glob(ctx, "**/go.mod")
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, file := range files {
+		dir := path.Dir(file)
+		if ignoredDir(dir) {
+			continue
+		}
+
+		paths = append(paths, dir)
+	}
+
+	slices.Sort(paths)
+	return paths, nil
+}
+
+func ignoredDir(dir string) bool {
+	if dir == "." {
+		return false
+	}
+
+	for _, elem := range strings.Split(dir, "/") {
+		if elem == "vendor" || elem == "testdata" || strings.HasPrefix(elem, ".") || strings.HasPrefix(elem, "_") {
+			return true
+		}
+	}
+	return false
+}
+
+// The module at the given path within the workspace.
+func (w *Workspace) Module(path string) *Mod {
+	return w.Go.Module(w.Source, path)
+}
+
+// Every module in the workspace, in the same order as Paths.
+func (w *Workspace) Modules(ctx context.Context) ([]*Mod, error) {
+	paths, err := w.Paths(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mods := make([]*Mod, len(paths))
+	for i, p := range paths {
+		mods[i] = w.Module(p)
+	}
+
+	return mods, nil
+}
+
+// WorkspaceModuleResult
+type WorkspaceModuleResult struct {
+	// Path of the module within the workspace.
+	Path string
+
+	// Whether continuous integration passed for the module.
+	Passed bool
+
+	// Reason continuous integration failed, if it did.
+	Error string
+}
+
+// WorkspaceCiReport
+type WorkspaceCiReport struct {
+	// Result of each module, in the same order as Paths.
+	Modules []*WorkspaceModuleResult
+}
+
+// Fail Ci of a module when its test coverage is below these minimum
+// percentages.
+func (w *Workspace) WithCoverageThreshold(
+	// Minimum percentage of statements covered across all packages.
+	// +optional
+	total float64,
+
+	// Minimum percentage of statements covered within each package.
+	// +optional
+	perPackage float64,
+) *Workspace {
+	w.CoverageThreshold = total
+	w.PackageCoverageThreshold = perPackage
+	return w
+}
+
+// Fail Ci of a module if any lint finding is at or above this severity.
+func (w *Workspace) WithLintSeverity(severity LintSeverity) *Workspace {
+	w.LintSeverity = severity
+	return w
+}
+
+// Check every module for reachable vulnerabilities with govulncheck in Ci.
+func (w *Workspace) WithVulncheck(
+	// A local copy of the Go vulnerability database.
+	// +optional
+	db *dagger.Directory,
+
+	// Version of govulncheck to install, defaults to a pinned version.
+	// +optional
+	version string,
+
+	// A prebuilt govulncheck binary used instead of installing govulncheck.
+	// +optional
+	binary *dagger.File,
+) *Workspace {
+	w.VulncheckEnabled = true
+	w.VulnDb = db
+	w.VulncheckVersion = version
+	w.VulncheckBinary = binary
+	return w
+}
+
+// Validate the source code of every module is formatted in Ci.
+func (w *Workspace) WithFormat(
+	// +default="gofmt"
+	formatter Formatter,
+
+	// Import prefix of local packages, only supported by goimports.
+	// +optional
+	localPrefix string,
+
+	// Version of goimports or gofumpt to install, defaults to a pinned
+	// version of the formatter.
+	// +optional
+	version string,
+) *Workspace {
+	w.Formatter = formatter
+	w.LocalPrefix = localPrefix
+	w.FormatterVersion = version
+	return w
+}
+
+// library configures the module at the path with the checks enabled on
+// the workspace.
+func (w *Workspace) library(modPath string, linter Linter, staticAnalyzer StaticAnalyzer, dependencyScanner DependencyScanner) *Library {
+	lib := w.Module(modPath).
+		Library(linter, staticAnalyzer, dependencyScanner, nil).
+		WithCoverageThreshold(w.CoverageThreshold, w.PackageCoverageThreshold).
+		WithLintSeverity(w.LintSeverity)
+
+	if w.VulncheckEnabled {
+		lib = lib.WithVulncheck(w.VulnDb, w.VulncheckVersion, w.VulncheckBinary)
+	}
+	if w.Formatter != "" {
+		lib = lib.WithFormat(w.Formatter, w.LocalPrefix, w.FormatterVersion)
+	}
+
+	return lib
+}
+
+// Run continuous integration for every module in the workspace in
+// parallel. Failing modules do not stop the others, see Check.
+func (w *Workspace) Ci(
+	ctx context.Context,
+
+	// +optional
+	linter Linter,
+
+	// +optional
+	staticAnalyzer StaticAnalyzer,
+
+	// +optional
+	dependencyScanner DependencyScanner,
+) (*WorkspaceCiReport, error) {
+	paths, err := w.Paths(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p := pool.New().WithContext(ctx)
+
+	report := &WorkspaceCiReport{
+		Modules: make([]*WorkspaceModuleResult, len(paths)),
+	}
+	for i, modPath := range paths {
+		p.Go(func(ctx context.Context) error {
+			err := w.library(modPath, linter, staticAnalyzer, dependencyScanner).Ci(ctx)
+
+			result := &WorkspaceModuleResult{
+				Path:   modPath,
+				Passed: err == nil,
+			}
+			if err != nil {
+				result.Error = err.Error()
+			}
+
+			report.Modules[i] = result
+			return nil
+		})
+	}
+
+	err = p.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// Whether continuous integration passed for every module.
+func (r *WorkspaceCiReport) Passed() bool {
+	for _, result := range r.Modules {
+		if !result.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Format the report as a line per module.
+func (r *WorkspaceCiReport) String() string {
+	var sb strings.Builder
+	for _, result := range r.Modules {
+		if result.Passed {
+			fmt.Fprintf(&sb, "ok\t%s\n", result.Path)
+			continue
+		}
+
+		fmt.Fprintf(&sb, "FAIL\t%s\n", result.Path)
+		for _, line := range strings.Split(strings.TrimSpace(result.Error), "\n") {
+			fmt.Fprintf(&sb, "\t%s\n", line)
+		}
+	}
+	return sb.String()
+}
+
+// Validate continuous integration passed for every module.
+func (r *WorkspaceCiReport) Check() error {
+	var failed []string
+	for _, result := range r.Modules {
+		if !result.Passed {
+			failed = append(failed, result.Path)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf(
+			"continuous integration failed for %d of %d module(s): %s\n%s",
+			len(failed),
+			len(r.Modules),
+			strings.Join(failed, ", "),
+			r.String(),
+		)
+	}
+
+	return nil
+}
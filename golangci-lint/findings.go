@@ -0,0 +1,172 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+
+	"dagger/golangci-lint/internal/dagger"
+)
+
+// Finding
+type Finding struct {
+	// Path of the file containing the finding.
+	File string
+
+	// Line number of the finding within the file.
+	Line int
+
+	// Linter which reported the finding.
+	Rule string
+
+	// Severity of the finding.
+	Severity string
+
+	// Description of the finding.
+	Message string
+}
+
+// Parse a report returned by Lint into individual findings.
+func (m *GolangciLint) Findings(
+	ctx context.Context,
+
+	// Unused, but required by the go Linter interface.
+	ctr *dagger.Container,
+
+	report *dagger.File,
+) ([]*Finding, error) {
+	contents, err := report.Contents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch m.Format {
+	case Json:
+		return parseJsonFindings([]byte(contents))
+	case Checkstyle:
+		return parseCheckstyleFindings([]byte(contents))
+	case Sarif:
+		return parseSarifFindings([]byte(contents))
+	default:
+		return nil, fmt.Errorf("unsupported report format: %s", m.Format)
+	}
+}
+
+func parseJsonFindings(b []byte) ([]*Finding, error) {
+	var report struct {
+		Issues []struct {
+			FromLinter string
+			Text       string
+			Severity   string
+			Pos        struct {
+				Filename string
+				Line     int
+			}
+		}
+	}
+	err := json.Unmarshal(b, &report)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make([]*Finding, 0, len(report.Issues))
+	for _, issue := range report.Issues {
+		findings = append(findings, &Finding{
+			File:     issue.Pos.Filename,
+			Line:     issue.Pos.Line,
+			Rule:     issue.FromLinter,
+			Severity: issue.Severity,
+			Message:  issue.Text,
+		})
+	}
+
+	return findings, nil
+}
+
+func parseCheckstyleFindings(b []byte) ([]*Finding, error) {
+	var report struct {
+		Files []struct {
+			Name   string `xml:"name,attr"`
+			Errors []struct {
+				Line     int    `xml:"line,attr"`
+				Severity string `xml:"severity,attr"`
+				Message  string `xml:"message,attr"`
+				Source   string `xml:"source,attr"`
+			} `xml:"error"`
+		} `xml:"file"`
+	}
+	err := xml.Unmarshal(b, &report)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := []*Finding{}
+	for _, file := range report.Files {
+		for _, e := range file.Errors {
+			findings = append(findings, &Finding{
+				File:     file.Name,
+				Line:     e.Line,
+				Rule:     e.Source,
+				Severity: e.Severity,
+				Message:  e.Message,
+			})
+		}
+	}
+
+	return findings, nil
+}
+
+func parseSarifFindings(b []byte) ([]*Finding, error) {
+	var report struct {
+		Runs []struct {
+			Results []struct {
+				RuleID  string `json:"ruleId"`
+				Level   string `json:"level"`
+				Message struct {
+					Text string `json:"text"`
+				} `json:"message"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	err := json.Unmarshal(b, &report)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := []*Finding{}
+	for _, run := range report.Runs {
+		for _, result := range run.Results {
+			f := &Finding{
+				Rule:     result.RuleID,
+				Severity: result.Level,
+				Message:  result.Message.Text,
+			}
+
+			if len(result.Locations) > 0 {
+				loc := result.Locations[0].PhysicalLocation
+				f.File = loc.ArtifactLocation.URI
+				f.Line = loc.Region.StartLine
+			}
+
+			findings = append(findings, f)
+		}
+	}
+
+	return findings, nil
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"strings"
+
+	"dagger/golangci-lint/internal/dagger"
+)
+
+type ReportFormat string
+
+const (
+	Checkstyle ReportFormat = "checkstyle"
+	Sarif      ReportFormat = "sarif"
+	Json       ReportFormat = "json"
+)
+
+// GolangciLint
+type GolangciLint struct {
+	// Container which the golangci-lint binary is copied from.
+	Container *dagger.Container
+
+	// +private
+	Config *dagger.File
+
+	// +private
+	Enable []string
+
+	// +private
+	Disable []string
+
+	// +private
+	Format ReportFormat
+
+	// +private
+	Timeout string
+}
+
+func New(
+	// +default="v2.1.6"
+	version string,
+
+	// Configuration file to use instead of any found within the source directory.
+	// +optional
+	config *dagger.File,
+
+	// Linters to enable in addition to those enabled by the configuration.
+	// +optional
+	enable []string,
+
+	// Linters to disable.
+	// +optional
+	disable []string,
+
+	// +default="json"
+	format ReportFormat,
+
+	// +default="5m"
+	timeout string,
+
+	// +optional
+	from *dagger.Container,
+) *GolangciLint {
+	if from == nil {
+		from = dag.Container().From("golangci/golangci-lint:" + version)
+	}
+
+	return &GolangciLint{
+		Container: from,
+		Config:    config,
+		Enable:    enable,
+		Disable:   disable,
+		Format:    format,
+		Timeout:   timeout,
+	}
+}
+
+// Lint the Go module mounted in the given container.
+//
+// The container is expected to be configured for the module, with its
+// source directory as the working directory and Go caches mounted, which
+// is the case for containers provided by the go module.
+func (m *GolangciLint) Lint(ctr *dagger.Container) *dagger.File {
+	reportPath := "/tmp/golangci-lint/report." + m.Format.extension()
+
+	args := []string{
+		"golangci-lint",
+		"run",
+		"--issues-exit-code", "0",
+		"--timeout", m.Timeout,
+		"--output.text.path", "stderr",
+		"--output." + string(m.Format) + ".path", reportPath,
+	}
+
+	if len(m.Enable) > 0 {
+		args = append(args, "--enable", strings.Join(m.Enable, ","))
+	}
+
+	if len(m.Disable) > 0 {
+		args = append(args, "--disable", strings.Join(m.Disable, ","))
+	}
+
+	ctr = ctr.
+		WithFile("/usr/local/bin/golangci-lint", m.Container.File("/usr/bin/golangci-lint")).
+		WithMountedCache("/root/.cache/golangci-lint", dag.CacheVolume("github.com/z5labs/daggerverse/golangci-lint")).
+		WithDirectory("/tmp/golangci-lint", dag.Directory())
+
+	if m.Config != nil {
+		const configPath = "/etc/golangci-lint/.golangci.yml"
+
+		ctr = ctr.WithMountedFile(configPath, m.Config)
+		args = append(args, "--config", configPath)
+	}
+
+	args = append(args, "./...")
+
+	return ctr.
+		WithExec(args).
+		File(reportPath)
+}
+
+func (f ReportFormat) extension() string {
+	switch f {
+	case Checkstyle:
+		return "xml"
+	default:
+		return string(f)
+	}
+}
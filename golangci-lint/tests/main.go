@@ -0,0 +1,196 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+
+	"dagger/golangci-lint-tests/internal/dagger"
+
+	"github.com/sourcegraph/conc/pool"
+)
+
+type GolangciLintTests struct{}
+
+func (m *GolangciLintTests) All(ctx context.Context) error {
+	ep := pool.New().WithErrors().WithContext(ctx)
+
+	ep.Go(m.JsonTest)
+	ep.Go(m.CheckstyleTest)
+	ep.Go(m.SarifTest)
+	ep.Go(m.ConfigTest)
+	ep.Go(m.DisableTest)
+	ep.Go(m.LintCheckTest)
+	ep.Go(m.LintCheckSarifTest)
+
+	return ep.Wait()
+}
+
+func (m *GolangciLintTests) lint(linter *dagger.GolangciLint) *dagger.File {
+	return m.library(linter).Lint()
+}
+
+type jsonReport struct {
+	Issues []struct {
+		FromLinter string
+		Text       string
+	}
+}
+
+func (m *GolangciLintTests) JsonTest(ctx context.Context) error {
+	contents, err := m.lint(dag.GolangciLint()).Contents(ctx)
+	if err != nil {
+		return err
+	}
+
+	var report jsonReport
+	err = json.Unmarshal([]byte(contents), &report)
+	if err != nil {
+		return err
+	}
+
+	if len(report.Issues) != 1 {
+		return fmt.Errorf("expected 1 issue but received: %d", len(report.Issues))
+	}
+
+	if report.Issues[0].FromLinter != "errcheck" {
+		return errors.New("expected issue to be reported by errcheck: " + report.Issues[0].FromLinter)
+	}
+
+	return nil
+}
+
+func (m *GolangciLintTests) CheckstyleTest(ctx context.Context) error {
+	contents, err := m.lint(dag.GolangciLint(dagger.GolangciLintOpts{
+		Format: dagger.GolangciLintReportFormatCheckstyle,
+	})).Contents(ctx)
+	if err != nil {
+		return err
+	}
+
+	var report struct {
+		Files []struct {
+			Name   string `xml:"name,attr"`
+			Errors []struct {
+				Source string `xml:"source,attr"`
+			} `xml:"error"`
+		} `xml:"file"`
+	}
+	err = xml.Unmarshal([]byte(contents), &report)
+	if err != nil {
+		return err
+	}
+
+	if len(report.Files) != 1 || len(report.Files[0].Errors) != 1 {
+		return fmt.Errorf("expected 1 file with 1 error: %v", report.Files)
+	}
+
+	return nil
+}
+
+func (m *GolangciLintTests) SarifTest(ctx context.Context) error {
+	contents, err := m.lint(dag.GolangciLint(dagger.GolangciLintOpts{
+		Format: dagger.GolangciLintReportFormatSarif,
+	})).Contents(ctx)
+	if err != nil {
+		return err
+	}
+
+	var report struct {
+		Runs []struct {
+			Results []struct {
+				RuleID string `json:"ruleId"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	err = json.Unmarshal([]byte(contents), &report)
+	if err != nil {
+		return err
+	}
+
+	if len(report.Runs) != 1 || len(report.Runs[0].Results) != 1 {
+		return fmt.Errorf("expected 1 run with 1 result: %v", report.Runs)
+	}
+
+	return nil
+}
+
+func (m *GolangciLintTests) ConfigTest(ctx context.Context) error {
+	contents, err := m.lint(dag.GolangciLint(dagger.GolangciLintOpts{
+		Config: dag.CurrentModule().Source().File("testdata/disable-errcheck.yml"),
+	})).Contents(ctx)
+	if err != nil {
+		return err
+	}
+
+	var report jsonReport
+	err = json.Unmarshal([]byte(contents), &report)
+	if err != nil {
+		return err
+	}
+
+	if len(report.Issues) != 0 {
+		return fmt.Errorf("expected config to disable errcheck: %v", report.Issues)
+	}
+
+	return nil
+}
+
+func (m *GolangciLintTests) DisableTest(ctx context.Context) error {
+	contents, err := m.lint(dag.GolangciLint(dagger.GolangciLintOpts{
+		Disable: []string{"errcheck"},
+	})).Contents(ctx)
+	if err != nil {
+		return err
+	}
+
+	if strings.Contains(contents, "errcheck") {
+		return errors.New("expected errcheck to be disabled: " + contents)
+	}
+
+	return nil
+}
+
+func (m *GolangciLintTests) library(linter *dagger.GolangciLint) *dagger.GoLibrary {
+	return dag.Go().
+		Module(dag.CurrentModule().Source().Directory("testdata/lint")).
+		Library(dagger.GoModLibraryOpts{
+			Linter: linter.AsGoLinter(),
+		})
+}
+
+func (m *GolangciLintTests) LintCheckTest(ctx context.Context) error {
+	lib := m.library(dag.GolangciLint())
+
+	err := lib.LintCheck(ctx, lib.Lint())
+	if err == nil {
+		return errors.New("expected lint check to fail due to errcheck finding")
+	}
+
+	if !strings.Contains(err.Error(), "lint.go:11") {
+		return errors.New("expected lint check to report finding location: " + err.Error())
+	}
+
+	return nil
+}
+
+func (m *GolangciLintTests) LintCheckSarifTest(ctx context.Context) error {
+	lib := m.library(dag.GolangciLint(dagger.GolangciLintOpts{
+		Format: dagger.GolangciLintReportFormatSarif,
+	}))
+
+	err := lib.LintCheck(ctx, lib.Lint())
+	if err == nil {
+		return errors.New("expected lint check to fail due to errcheck finding")
+	}
+
+	return nil
+}
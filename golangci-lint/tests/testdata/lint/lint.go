@@ -0,0 +1,12 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package lint
+
+import "os"
+
+func Unchecked() {
+	os.Remove("does-not-exist")
+}
@@ -28,7 +28,13 @@ func (t *Tests) All(ctx context.Context) error {
 		Version: "latest",
 	}).All)
 
+	ep.Go(dag.GolangciLintTests().All)
+
 	ep.Go(dag.ProtobufTests().All)
 
+	ep.Go(dag.QualityGateTests().All)
+
+	ep.Go(dag.TrivyTests().All)
+
 	return ep.Wait()
 }
@@ -20,3 +20,24 @@ func (*Noop) GoLinter() *GoLinter {
 func (*GoLinter) Lint(ctx context.Context, ctr *dagger.Container) *dagger.File {
 	return dag.File("noop_lint_report.txt", "")
 }
+
+type GoLintFinding struct {
+	File     string
+	Line     int
+	Rule     string
+	Severity string
+	Message  string
+}
+
+func (*GoLinter) Findings(
+	ctx context.Context,
+	ctr *dagger.Container,
+	report *dagger.File,
+) ([]*GoLintFinding, error) {
+	_, err := report.Sync(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*GoLintFinding{}, nil
+}
@@ -9,8 +9,6 @@ import (
 	"context"
 	"fmt"
 	"strings"
-
-	"dagger/protobuf/internal/dagger"
 )
 
 type Go struct {
@@ -38,17 +36,7 @@ func (m *Protobuf) Go(
 		archiveType,
 	))
 
-	var dir *dagger.Directory
-	switch archiveType {
-	case "zip":
-		dir = dag.Archive().Zip().Extract(plugin)
-	case "tar.gz":
-		dir = dag.Archive().Tar().Extract(plugin, dagger.ArchiveTarExtractOpts{
-			Gzip: true,
-		})
-	}
-
-	m = m.WithPlugin(name, dir.File(name))
+	m = m.WithPlugin(name, dag.Archive().File(plugin, name))
 
 	return &Go{
 		Protobuf: m,
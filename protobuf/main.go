@@ -74,15 +74,16 @@ func newContainer(version string, platform platforms.Platform) (*dagger.Containe
 		arch,
 	))
 
-	dir := dag.Archive().Zip().Extract(protoc)
+	dir := dag.Archive().Zip().Extract(protoc, dagger.ArchiveZipExtractOpts{
+		Include: []string{"bin/protoc", "include"},
+	})
 
 	c := dag.Container().
 		From("alpine").
 		WithDirectory("/protobuf", dir).
 		WithEnvVariable("PATH", "/protobuf/bin/:${PATH}", dagger.ContainerWithEnvVariableOpts{
 			Expand: true,
-		}).
-		WithExec([]string{"chmod", "+x", "/protobuf/bin/protoc"})
+		})
 
 	return c, nil
 }
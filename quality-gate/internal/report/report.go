@@ -0,0 +1,147 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// Package report parses coverage profiles and compares lint issues in the
+// minimal form needed to evaluate a quality gate.
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Coverage counts covered statements from a Go coverage profile.
+type Coverage struct {
+	Statements int
+	Covered    int
+}
+
+// Percent returns the percentage of covered statements.
+func (c Coverage) Percent() float64 {
+	if c.Statements == 0 {
+		return 100
+	}
+	return float64(c.Covered) / float64(c.Statements) * 100
+}
+
+// ParseCoverage reads a coverage profile as produced by go test -coverprofile.
+// Blocks reported more than once, e.g. by multiple test binaries, are only
+// counted once and are considered covered if any occurrence was executed.
+func ParseCoverage(r io.Reader) (Coverage, error) {
+	type block struct {
+		pos     string
+		numStmt int
+	}
+
+	covered := make(map[block]bool)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return Coverage{}, errors.New("malformed coverage block: " + line)
+		}
+
+		numStmt, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return Coverage{}, fmt.Errorf("malformed coverage block: %w", err)
+		}
+
+		count, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return Coverage{}, fmt.Errorf("malformed coverage block: %w", err)
+		}
+
+		b := block{pos: fields[0], numStmt: numStmt}
+		covered[b] = covered[b] || count > 0
+	}
+	if err := scanner.Err(); err != nil {
+		return Coverage{}, err
+	}
+
+	var c Coverage
+	for b, ok := range covered {
+		c.Statements += b.numStmt
+		if ok {
+			c.Covered += b.numStmt
+		}
+	}
+
+	return c, nil
+}
+
+// Issue is a single lint finding.
+type Issue struct {
+	File    string
+	Line    int
+	Rule    string
+	Message string
+}
+
+// Key identifies an issue independent of its line number so issues
+// are not reported as new when unrelated code above them changes.
+func (i Issue) Key() string {
+	return i.File + "\x00" + i.Rule + "\x00" + i.Message
+}
+
+// Format detects whether a lint report is golangci-lint JSON, SARIF or
+// Checkstyle XML, returning the matching golangci-lint report format. An
+// empty report has no format as it contains no issues.
+func Format(b []byte) (string, error) {
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 {
+		return "", nil
+	}
+
+	switch b[0] {
+	case '<':
+		return "checkstyle", nil
+	case '{':
+		var probe map[string]json.RawMessage
+		err := json.Unmarshal(b, &probe)
+		if err != nil {
+			return "", err
+		}
+
+		if _, ok := probe["runs"]; ok {
+			return "sarif", nil
+		}
+		return "json", nil
+	default:
+		return "", errors.New("unrecognized lint report format")
+	}
+}
+
+// New returns the issues which do not appear in the baseline. Repeated
+// issues are matched one-for-one so adding a duplicate is still reported.
+func New(issues, baseline []Issue) []Issue {
+	seen := make(map[string]int, len(baseline))
+	for _, issue := range baseline {
+		seen[issue.Key()]++
+	}
+
+	var added []Issue
+	for _, issue := range issues {
+		key := issue.Key()
+		if seen[key] > 0 {
+			seen[key]--
+			continue
+		}
+		added = append(added, issue)
+	}
+
+	return added
+}
@@ -0,0 +1,281 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"dagger/quality-gate/internal/dagger"
+	"dagger/quality-gate/internal/report"
+)
+
+// QualityGate
+type QualityGate struct {
+	// +private
+	BaselineCoverage *dagger.File
+
+	// +private
+	BaselineLintReport *dagger.File
+
+	// +private
+	MinCoverage float64
+
+	// +private
+	MaxCoverageDecrease float64
+
+	// +private
+	MaxNewIssues int
+}
+
+func New(
+	// Coverage profile to compare against, e.g. from the default branch.
+	// +optional
+	baselineCoverage *dagger.File,
+
+	// Lint report to compare against, e.g. from the default branch.
+	// +optional
+	baselineLintReport *dagger.File,
+
+	// Minimum percentage of statements covered.
+	// +optional
+	minCoverage float64,
+
+	// Maximum decrease in coverage, in percentage points, compared to the baseline.
+	// +optional
+	maxCoverageDecrease float64,
+
+	// Maximum number of lint issues not present in the baseline. Only
+	// checked when a baseline lint report is given, otherwise every issue
+	// would count as new.
+	// +optional
+	maxNewIssues int,
+) *QualityGate {
+	return &QualityGate{
+		BaselineCoverage:    baselineCoverage,
+		BaselineLintReport:  baselineLintReport,
+		MinCoverage:         minCoverage,
+		MaxCoverageDecrease: maxCoverageDecrease,
+		MaxNewIssues:        maxNewIssues,
+	}
+}
+
+// Result
+type Result struct {
+	// Whether all conditions of the quality gate were met.
+	Passed bool
+
+	// Percentage of statements covered.
+	Coverage float64
+
+	// Percentage of statements covered by the baseline.
+	BaselineCoverage float64
+
+	// Change in coverage, in percentage points, compared to the baseline.
+	CoverageDelta float64
+
+	// Number of lint issues.
+	Issues int
+
+	// Number of lint issues not present in the baseline, zero without a
+	// baseline lint report.
+	NewIssues int
+
+	// Conditions of the quality gate which were not met.
+	Failures []string
+}
+
+// Summarize the result in a human-readable form.
+func (r *Result) Summary() string {
+	var sb strings.Builder
+
+	status := "PASSED"
+	if !r.Passed {
+		status = "FAILED"
+	}
+
+	fmt.Fprintf(&sb, "Quality gate %s\n", status)
+	fmt.Fprintf(&sb, "  coverage:    %.1f%% (%+.1f%% against baseline of %.1f%%)\n", r.Coverage, r.CoverageDelta, r.BaselineCoverage)
+	fmt.Fprintf(&sb, "  lint issues: %d (%d new)\n", r.Issues, r.NewIssues)
+
+	for _, failure := range r.Failures {
+		fmt.Fprintf(&sb, "  - %s\n", failure)
+	}
+
+	return sb.String()
+}
+
+// Evaluate the quality gate against lint and coverage reports.
+func (m *QualityGate) Evaluate(
+	ctx context.Context,
+	lintReport *dagger.File,
+	coverageReport *dagger.File,
+) (*Result, error) {
+	coverage, err := parseCoverage(ctx, coverageReport)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without a baseline the current coverage is the baseline so the
+	// delta is zero and only the minimum coverage applies.
+	baselineCoverage := coverage
+	if m.BaselineCoverage != nil {
+		baselineCoverage, err = parseCoverage(ctx, m.BaselineCoverage)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	issues, err := parseIssues(ctx, lintReport)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Coverage:         coverage.Percent(),
+		BaselineCoverage: baselineCoverage.Percent(),
+		Issues:           len(issues),
+	}
+
+	// Without a baseline there is nothing to tell new issues apart from
+	// existing ones, so the new issue check is skipped.
+	if m.BaselineLintReport != nil {
+		baselineIssues, err := parseIssues(ctx, m.BaselineLintReport)
+		if err != nil {
+			return nil, err
+		}
+
+		result.NewIssues = len(report.New(issues, baselineIssues))
+	}
+	result.CoverageDelta = result.Coverage - result.BaselineCoverage
+
+	if result.Coverage < m.MinCoverage {
+		result.Failures = append(result.Failures, fmt.Sprintf(
+			"coverage %.1f%% is below minimum of %.1f%%",
+			result.Coverage,
+			m.MinCoverage,
+		))
+	}
+
+	if -result.CoverageDelta > m.MaxCoverageDecrease {
+		result.Failures = append(result.Failures, fmt.Sprintf(
+			"coverage decreased by %.1f%% which exceeds maximum decrease of %.1f%%",
+			-result.CoverageDelta,
+			m.MaxCoverageDecrease,
+		))
+	}
+
+	if result.NewIssues > m.MaxNewIssues {
+		result.Failures = append(result.Failures, fmt.Sprintf(
+			"%d new lint issue(s) exceeds maximum of %d",
+			result.NewIssues,
+			m.MaxNewIssues,
+		))
+	}
+
+	result.Passed = len(result.Failures) == 0
+
+	return result, nil
+}
+
+// Fail if the quality gate is not met.
+func (m *QualityGate) StaticAnalysis(
+	ctx context.Context,
+
+	// The quality gate only evaluates the reports, so the module
+	// container is ignored.
+	ctr *dagger.Container,
+
+	lintReport *dagger.File,
+	coverageReport *dagger.File,
+) error {
+	result, err := m.Evaluate(ctx, lintReport, coverageReport)
+	if err != nil {
+		return err
+	}
+
+	if !result.Passed {
+		return errors.New(result.Summary())
+	}
+
+	return nil
+}
+
+func parseCoverage(ctx context.Context, f *dagger.File) (report.Coverage, error) {
+	contents, err := f.Contents(ctx)
+	if err != nil {
+		return report.Coverage{}, err
+	}
+
+	return report.ParseCoverage(strings.NewReader(contents))
+}
+
+// parseIssues parses lint reports with the golangci-lint module so both
+// modules understand the same report formats.
+func parseIssues(ctx context.Context, f *dagger.File) ([]report.Issue, error) {
+	contents, err := f.Contents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	format, err := report.Format([]byte(contents))
+	if err != nil {
+		return nil, err
+	}
+	if format == "" {
+		return nil, nil
+	}
+
+	findings, err := dag.GolangciLint(dagger.GolangciLintOpts{
+		Format: dagger.GolangciLintReportFormat(format),
+	}).Findings(ctx, dag.Container(), f)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := make([]report.Issue, len(findings))
+	for i, finding := range findings {
+		issue, err := toIssue(ctx, &finding)
+		if err != nil {
+			return nil, err
+		}
+
+		issues[i] = issue
+	}
+
+	return issues, nil
+}
+
+func toIssue(ctx context.Context, finding *dagger.GolangciLintFinding) (report.Issue, error) {
+	file, err := finding.File(ctx)
+	if err != nil {
+		return report.Issue{}, err
+	}
+
+	line, err := finding.Line(ctx)
+	if err != nil {
+		return report.Issue{}, err
+	}
+
+	rule, err := finding.Rule(ctx)
+	if err != nil {
+		return report.Issue{}, err
+	}
+
+	message, err := finding.Message(ctx)
+	if err != nil {
+		return report.Issue{}, err
+	}
+
+	return report.Issue{
+		File:    file,
+		Line:    line,
+		Rule:    rule,
+		Message: message,
+	}, nil
+}
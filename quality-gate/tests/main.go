@@ -0,0 +1,179 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"dagger/quality-gate-tests/internal/dagger"
+
+	"github.com/sourcegraph/conc/pool"
+)
+
+type QualityGateTests struct{}
+
+func (m *QualityGateTests) All(ctx context.Context) error {
+	ep := pool.New().WithErrors().WithContext(ctx)
+
+	ep.Go(m.PassTest)
+	ep.Go(m.MinCoverageTest)
+	ep.Go(m.CoverageDecreaseTest)
+	ep.Go(m.NewIssuesTest)
+	ep.Go(m.NoBaselineLintReportTest)
+	ep.Go(m.LibraryStaticAnalysisTest)
+
+	return ep.Wait()
+}
+
+func testdata(name string) *dagger.File {
+	return dag.CurrentModule().Source().File("testdata/" + name)
+}
+
+func (m *QualityGateTests) PassTest(ctx context.Context) error {
+	result := dag.QualityGate(dagger.QualityGateOpts{
+		BaselineCoverage:   testdata("coverage-full.out"),
+		BaselineLintReport: testdata("lint-baseline.json"),
+	}).Evaluate(testdata("lint-baseline.json"), testdata("coverage-full.out"))
+
+	passed, err := result.Passed(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !passed {
+		summary, _ := result.Summary(ctx)
+		return errors.New("expected quality gate to pass: " + summary)
+	}
+
+	return nil
+}
+
+func (m *QualityGateTests) MinCoverageTest(ctx context.Context) error {
+	result := dag.QualityGate(dagger.QualityGateOpts{
+		MinCoverage: 80,
+	}).Evaluate(dag.File("lint.json", ""), testdata("coverage-half.out"))
+
+	coverage, err := result.Coverage(ctx)
+	if err != nil {
+		return err
+	}
+
+	if coverage != 50 {
+		return fmt.Errorf("expected coverage of 50%% but received: %.1f%%", coverage)
+	}
+
+	passed, err := result.Passed(ctx)
+	if err != nil {
+		return err
+	}
+
+	if passed {
+		return errors.New("expected quality gate to fail due to coverage below minimum")
+	}
+
+	return nil
+}
+
+func (m *QualityGateTests) CoverageDecreaseTest(ctx context.Context) error {
+	result := dag.QualityGate(dagger.QualityGateOpts{
+		BaselineCoverage:    testdata("coverage-full.out"),
+		MaxCoverageDecrease: 10,
+	}).Evaluate(dag.File("lint.json", ""), testdata("coverage-half.out"))
+
+	delta, err := result.CoverageDelta(ctx)
+	if err != nil {
+		return err
+	}
+
+	if delta != -50 {
+		return fmt.Errorf("expected coverage delta of -50%% but received: %.1f%%", delta)
+	}
+
+	failures, err := result.Failures(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(failures) != 1 {
+		return fmt.Errorf("expected only coverage decrease to fail the quality gate: %v", failures)
+	}
+
+	return nil
+}
+
+func (m *QualityGateTests) NewIssuesTest(ctx context.Context) error {
+	result := dag.QualityGate(dagger.QualityGateOpts{
+		BaselineLintReport: testdata("lint-baseline.json"),
+	}).Evaluate(testdata("lint-new-issue.json"), testdata("coverage-full.out"))
+
+	issues, err := result.Issues(ctx)
+	if err != nil {
+		return err
+	}
+
+	if issues != 2 {
+		return fmt.Errorf("expected 2 lint issues but received: %d", issues)
+	}
+
+	newIssues, err := result.NewIssues(ctx)
+	if err != nil {
+		return err
+	}
+
+	if newIssues != 1 {
+		return fmt.Errorf("expected moved issue to match baseline leaving 1 new issue but received: %d", newIssues)
+	}
+
+	passed, err := result.Passed(ctx)
+	if err != nil {
+		return err
+	}
+
+	if passed {
+		return errors.New("expected quality gate to fail due to new lint issue")
+	}
+
+	return nil
+}
+
+func (m *QualityGateTests) NoBaselineLintReportTest(ctx context.Context) error {
+	result := dag.QualityGate().
+		Evaluate(testdata("lint-new-issue.json"), testdata("coverage-full.out"))
+
+	newIssues, err := result.NewIssues(ctx)
+	if err != nil {
+		return err
+	}
+
+	if newIssues != 0 {
+		return fmt.Errorf("expected no new issues without a baseline but received: %d", newIssues)
+	}
+
+	passed, err := result.Passed(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !passed {
+		summary, _ := result.Summary(ctx)
+		return errors.New("expected quality gate to pass without a baseline: " + summary)
+	}
+
+	return nil
+}
+
+func (m *QualityGateTests) LibraryStaticAnalysisTest(ctx context.Context) error {
+	return dag.Go().
+		Module(dag.CurrentModule().Source().Directory("testdata/library")).
+		Library(dagger.GoModLibraryOpts{
+			StaticAnalyzer: dag.QualityGate(dagger.QualityGateOpts{
+				MinCoverage: 100,
+			}).AsGoStaticAnalyzer(),
+		}).
+		Ci(ctx)
+}
@@ -0,0 +1,14 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package library
+
+import "testing"
+
+func TestHelloWorld(t *testing.T) {
+	if s := HelloWorld(); s != "hello world" {
+		t.Errorf("unexpected greeting: %s", s)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"dagger/trivy/internal/dagger"
+)
+
+const (
+	cacheDir  = "/root/.cache/trivy"
+	exportDir = "/export"
+)
+
+// Use a pre-downloaded vulnerability database, e.g. as returned by ExportDb,
+// instead of downloading it at scan time.
+func (m *Trivy) WithDb(
+	// Directory containing trivy.db and metadata.json.
+	db *dagger.Directory,
+) *Trivy {
+	m.VulnDb = db
+	return m
+}
+
+// Use a pre-downloaded vulnerability database archive, e.g. the db.tar.gz
+// pulled from ghcr.io/aquasecurity/trivy-db with oras.
+func (m *Trivy) WithDbArchive(archive *dagger.File) *Trivy {
+	return m.WithDb(dag.Archive().Extract(archive))
+}
+
+// Use a pre-downloaded Java database, e.g. as returned by ExportJavaDb,
+// instead of downloading it at scan time.
+func (m *Trivy) WithJavaDb(
+	// Directory containing trivy-java.db and metadata.json.
+	db *dagger.Directory,
+) *Trivy {
+	m.JavaDb = db
+	return m
+}
+
+// Use a pre-downloaded Java database archive, e.g. the javadb.tar.gz
+// pulled from ghcr.io/aquasecurity/trivy-java-db with oras.
+func (m *Trivy) WithJavaDbArchive(archive *dagger.File) *Trivy {
+	return m.WithJavaDb(dag.Archive().Extract(archive))
+}
+
+// Use a pre-downloaded misconfiguration checks bundle, e.g. as returned by
+// ExportChecks, instead of downloading it at scan time.
+func (m *Trivy) WithChecks(
+	// Directory containing the checks bundle content and metadata.json.
+	checks *dagger.Directory,
+) *Trivy {
+	m.Checks = checks
+	return m
+}
+
+// Export the vulnerability database, downloading it if one was not
+// imported, so it can be mirrored to environments without internet access.
+func (m *Trivy) ExportDb() *dagger.Directory {
+	if m.VulnDb != nil {
+		return m.VulnDb
+	}
+
+	return m.Ctr.
+		WithExec([]string{"trivy", "image", "--download-db-only", "--cache-dir", exportDir}).
+		Directory(exportDir + "/db")
+}
+
+// Export the Java database, downloading it if one was not imported,
+// so it can be mirrored to environments without internet access.
+func (m *Trivy) ExportJavaDb() *dagger.Directory {
+	if m.JavaDb != nil {
+		return m.JavaDb
+	}
+
+	return m.Ctr.
+		WithExec([]string{"trivy", "image", "--download-java-db-only", "--cache-dir", exportDir}).
+		Directory(exportDir + "/java-db")
+}
+
+// Export the misconfiguration checks bundle, downloading it if one was not
+// imported, so it can be mirrored to environments without internet access.
+func (m *Trivy) ExportChecks() *dagger.Directory {
+	if m.Checks != nil {
+		return m.Checks
+	}
+
+	// Trivy has no flag to only download the checks bundle, so an empty
+	// directory is scanned instead.
+	return m.Ctr.
+		WithMountedDirectory("/scan/empty", dag.Directory()).
+		WithExec([]string{"trivy", "config", "--quiet", "--cache-dir", exportDir, "/scan/empty"}).
+		Directory(exportDir + "/policy")
+}
+
+// withDb mounts any imported databases over the cache and adds the flags
+// controlling database updates and network access during a scan.
+func (m *Trivy) withDb(scanner *dagger.Container, args []string) (*dagger.Container, []string) {
+	if m.VulnDb != nil {
+		scanner = scanner.WithMountedDirectory(cacheDir+"/db", m.VulnDb)
+	}
+	if m.SkipDbUpdate || m.VulnDb != nil {
+		args = append(args, "--skip-db-update")
+	}
+
+	if m.JavaDb != nil {
+		scanner = scanner.WithMountedDirectory(cacheDir+"/java-db", m.JavaDb)
+	}
+	if m.SkipDbUpdate || m.JavaDb != nil {
+		args = append(args, "--skip-java-db-update")
+	}
+
+	if m.Offline {
+		args = append(args, "--offline-scan")
+	}
+
+	return scanner, args
+}
+
+// withChecks mounts any imported checks bundle over the cache and skips
+// updating it when databases are not updated or no network requests may
+// be made, falling back to the checks embedded in trivy when none are
+// cached.
+func (m *Trivy) withChecks(scanner *dagger.Container, args []string) (*dagger.Container, []string) {
+	if m.Checks != nil {
+		scanner = scanner.WithMountedDirectory(cacheDir+"/policy", m.Checks)
+	}
+	if m.SkipDbUpdate || m.Offline || m.Checks != nil {
+		args = append(args, "--skip-check-update")
+	}
+
+	return scanner, args
+}
@@ -16,12 +16,35 @@ package main
 
 import (
 	"context"
-	"fmt"
+	"slices"
 	"strings"
 
 	"dagger/trivy/internal/dagger"
 )
 
+type ReportFormat string
+
+const (
+	Table     ReportFormat = "table"
+	Json      ReportFormat = "json"
+	Sarif     ReportFormat = "sarif"
+	Cyclonedx ReportFormat = "cyclonedx"
+	Spdx      ReportFormat = "spdx-json"
+)
+
+func (f ReportFormat) extension() string {
+	switch f {
+	case Table:
+		return "txt"
+	case Cyclonedx:
+		return "cdx.json"
+	case Spdx:
+		return "spdx.json"
+	default:
+		return string(f)
+	}
+}
+
 type Trivy struct {
 	Ctr *dagger.Container
 
@@ -29,7 +52,34 @@ type Trivy struct {
 	Severity []string
 
 	// +private
-	Format string
+	Format ReportFormat
+
+	// +private
+	FailOnSeverity []string
+
+	// +private
+	IgnoreUnfixed bool
+
+	// +private
+	IgnoreFile *dagger.File
+
+	// +private
+	IgnorePolicy *dagger.File
+
+	// +private
+	SkipDbUpdate bool
+
+	// +private
+	Offline bool
+
+	// +private
+	VulnDb *dagger.Directory
+
+	// +private
+	JavaDb *dagger.Directory
+
+	// +private
+	Checks *dagger.Directory
 }
 
 func New(
@@ -39,48 +89,166 @@ func New(
 	// +default=["UNKNOWN", "LOW", "MEDIUM", "HIGH", "CRITICAL"]
 	severity []string,
 
+	// Default format of reports returned by Report.
 	// +default="table"
-	format string,
+	format ReportFormat,
+
+	// Fail scans if any finding has one of these severities. Scans fail
+	// on HIGH and CRITICAL findings by default, where they previously
+	// only reported them, so use reportOnly to keep that behavior.
+	// +default=["HIGH", "CRITICAL"]
+	failOnSeverity []string,
+
+	// Return reports without failing scans on any finding, ignoring
+	// failOnSeverity.
+	// +optional
+	reportOnly bool,
+
+	// Exclude vulnerabilities which have no fix available.
+	// +optional
+	ignoreUnfixed bool,
+
+	// A .trivyignore file, or .trivyignore.yaml when named with a yaml extension.
+	// +optional
+	ignoreFile *dagger.File,
+
+	// A Rego policy for ignoring vulnerabilities.
+	// +optional
+	ignorePolicy *dagger.File,
+
+	// Use the vulnerability and Java databases and the misconfiguration
+	// checks bundle already in the cache instead of updating them.
+	// Implied when a database or checks bundle is imported.
+	// +optional
+	skipDbUpdate bool,
+
+	// Do not make any network requests while scanning, e.g. to look up
+	// Java artifacts which are not in the Java database or to update the
+	// misconfiguration checks bundle.
+	// +optional
+	offline bool,
 ) *Trivy {
+	if reportOnly {
+		failOnSeverity = nil
+	}
+
 	ctr := dag.Container().
 		From("aquasec/trivy:"+imageTag).
 		WithMountedCache("/root/.cache/trivy", dag.CacheVolume("github.com/z5labs/daggerverse/trivy"))
 
 	return &Trivy{
-		Ctr:      ctr,
-		Severity: severity,
-		Format:   format,
+		Ctr:            ctr,
+		Severity:       severity,
+		Format:         format,
+		FailOnSeverity: failOnSeverity,
+		IgnoreUnfixed:  ignoreUnfixed,
+		IgnoreFile:     ignoreFile,
+		IgnorePolicy:   ignorePolicy,
+		SkipDbUpdate:   skipDbUpdate,
+		Offline:        offline,
 	}
 }
 
-// Scan a container for vulnerabilities.
-func (m *Trivy) ScanContainer(ctx context.Context, ctr *dagger.Container) error {
-	platform, err := ctr.Platform(ctx)
+// Scan a container for vulnerabilities and return the report.
+func (m *Trivy) Report(
+	ctx context.Context,
+
+	ctr *dagger.Container,
+
+	// Format of the report, defaults to the format given to New.
+	// +optional
+	format ReportFormat,
+) (*dagger.File, error) {
+	t, err := imageTarget(ctx, ctr)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	stdout, err := m.Ctr.
-		WithMountedFile("/scan/ctr.tar", ctr.AsTarball()).
-		WithExec([]string{
-			"trivy",
-			"image",
-			"--quiet",
-			"--platform",
-			string(platform),
-			"--severity",
-			strings.Join(m.Severity, ","),
-			"--format",
-			m.Format,
-			"--input",
-			"/scan/ctr.tar",
-		}).
-		Stdout(ctx)
+	return m.report(ctx, t, format)
+}
+
+// Scan a container for vulnerabilities and return the JSON report,
+// which can be parsed by Vulnerabilities. Fails if any vulnerability
+// has one of the severities given by failOnSeverity.
+func (m *Trivy) ScanContainer(ctx context.Context, ctr *dagger.Container) (*dagger.File, error) {
+	t, err := imageTarget(ctx, ctr)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	fmt.Println(stdout)
+	return m.check(ctx, t)
+}
+
+// Scan a directory, e.g. a Go module, for vulnerable dependencies and secrets.
+// Fails if any finding has one of the severities given by failOnSeverity.
+func (m *Trivy) ScanDirectory(
+	ctx context.Context,
+
+	dir *dagger.Directory,
+
+	// Format of the report, defaults to the format given to New.
+	// +optional
+	format ReportFormat,
+
+	// +default=["vuln", "secret"]
+	scanners []string,
+) (*dagger.File, error) {
+	t := target{
+		command: "fs",
+		args:    []string{"--scanners", strings.Join(scanners, ","), "/scan/src"},
+		mount: func(ctr *dagger.Container) *dagger.Container {
+			return ctr.WithMountedDirectory("/scan/src", dir)
+		},
+		vulnerabilities:   true,
+		misconfigurations: slices.Contains(scanners, "misconfig"),
+	}
+
+	return m.checkAndReport(ctx, t, format)
+}
+
+// Scan a directory of infrastructure as code, e.g. Dockerfiles, Kubernetes
+// manifests or Terraform, for misconfigurations. Fails if any finding has
+// one of the severities given by failOnSeverity.
+func (m *Trivy) ScanConfig(
+	ctx context.Context,
+
+	dir *dagger.Directory,
+
+	// Format of the report, defaults to the format given to New.
+	// +optional
+	format ReportFormat,
+) (*dagger.File, error) {
+	t := target{
+		command: "config",
+		args:    []string{"/scan/src"},
+		mount: func(ctr *dagger.Container) *dagger.Container {
+			return ctr.WithMountedDirectory("/scan/src", dir)
+		},
+		misconfigurations: true,
+	}
+
+	return m.checkAndReport(ctx, t, format)
+}
+
+// Scan a CycloneDX or SPDX SBOM for vulnerabilities. Fails if any
+// vulnerability has one of the severities given by failOnSeverity.
+func (m *Trivy) ScanSbom(
+	ctx context.Context,
+
+	sbom *dagger.File,
+
+	// Format of the report, defaults to the format given to New.
+	// +optional
+	format ReportFormat,
+) (*dagger.File, error) {
+	t := target{
+		command: "sbom",
+		args:    []string{"/scan/sbom.json"},
+		mount: func(ctr *dagger.Container) *dagger.Container {
+			return ctr.WithMountedFile("/scan/sbom.json", sbom)
+		},
+		vulnerabilities: true,
+	}
 
-	return nil
+	return m.checkAndReport(ctx, t, format)
 }
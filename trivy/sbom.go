@@ -0,0 +1,78 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"dagger/trivy/internal/dagger"
+)
+
+// sbomFormat maps the SBOM format names shared with other modules, which
+// cannot reference this module's enums, to the trivy report format.
+func sbomFormat(format string) (ReportFormat, error) {
+	switch format {
+	case "cyclonedx":
+		return Cyclonedx, nil
+	case "spdx", "spdx-json":
+		return Spdx, nil
+	default:
+		return "", fmt.Errorf("unsupported sbom format: %s", format)
+	}
+}
+
+// Generate an SBOM listing the packages installed in a container,
+// including Go modules recorded in the build info of Go binaries.
+func (m *Trivy) ContainerSbom(
+	ctx context.Context,
+
+	ctr *dagger.Container,
+
+	// Either cyclonedx or spdx.
+	format string,
+) (*dagger.File, error) {
+	f, err := sbomFormat(format)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := imageTarget(ctx, ctr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the package inventory is needed, not the vulnerability database.
+	t.vulnerabilities = false
+
+	return m.report(ctx, t, f)
+}
+
+// Generate an SBOM listing the dependencies of a directory, e.g. those
+// required by the go.mod of a Go module.
+func (m *Trivy) DirectorySbom(
+	ctx context.Context,
+
+	dir *dagger.Directory,
+
+	// Either cyclonedx or spdx.
+	format string,
+) (*dagger.File, error) {
+	f, err := sbomFormat(format)
+	if err != nil {
+		return nil, err
+	}
+
+	t := target{
+		command: "fs",
+		args:    []string{"/scan/src"},
+		mount: func(ctr *dagger.Container) *dagger.Container {
+			return ctr.WithMountedDirectory("/scan/src", dir)
+		},
+	}
+
+	return m.report(ctx, t, f)
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"dagger/trivy/internal/dagger"
+)
+
+// target describes what trivy scans and how it is mounted into the
+// trivy container.
+type target struct {
+	// Trivy subcommand, e.g. image or fs.
+	command string
+
+	// Arguments identifying the target, appended after all other flags.
+	args []string
+
+	mount func(*dagger.Container) *dagger.Container
+
+	// Whether the subcommand scans for vulnerabilities and so
+	// accepts vulnerability specific flags, e.g. --ignore-unfixed.
+	vulnerabilities bool
+
+	// Whether the subcommand scans for misconfigurations and so
+	// needs the checks bundle.
+	misconfigurations bool
+}
+
+func imageTarget(ctx context.Context, ctr *dagger.Container) (target, error) {
+	platform, err := ctr.Platform(ctx)
+	if err != nil {
+		return target{}, err
+	}
+
+	t := target{
+		command: "image",
+		args:    []string{"--platform", string(platform), "--input", "/scan/ctr.tar"},
+		mount: func(scanner *dagger.Container) *dagger.Container {
+			return scanner.WithMountedFile("/scan/ctr.tar", ctr.AsTarball())
+		},
+		vulnerabilities: true,
+	}
+	return t, nil
+}
+
+func (m *Trivy) report(ctx context.Context, t target, format ReportFormat) (*dagger.File, error) {
+	if format == "" {
+		format = m.Format
+	}
+
+	output := "/scan/report." + format.extension()
+
+	args := []string{
+		"trivy",
+		t.command,
+		"--quiet",
+		"--severity",
+		strings.Join(m.Severity, ","),
+		"--format",
+		string(format),
+		"--output",
+		output,
+	}
+
+	scanner := t.mount(m.Ctr)
+
+	if t.vulnerabilities {
+		if m.IgnoreUnfixed {
+			args = append(args, "--ignore-unfixed")
+		}
+
+		scanner, args = m.withDb(scanner, args)
+	}
+
+	if t.misconfigurations {
+		scanner, args = m.withChecks(scanner, args)
+	}
+
+	if m.IgnoreFile != nil {
+		name, err := m.IgnoreFile.Name(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		// Trivy only parses the YAML format when the file has a yaml extension.
+		ignoreFile := "/scan/.trivyignore"
+		if ext := path.Ext(name); ext == ".yaml" || ext == ".yml" {
+			ignoreFile += ext
+		}
+
+		scanner = scanner.WithMountedFile(ignoreFile, m.IgnoreFile)
+		args = append(args, "--ignorefile", ignoreFile)
+	}
+
+	if m.IgnorePolicy != nil {
+		scanner = scanner.WithMountedFile("/scan/ignore.rego", m.IgnorePolicy)
+		args = append(args, "--ignore-policy", "/scan/ignore.rego")
+	}
+
+	args = append(args, t.args...)
+
+	report := scanner.
+		WithExec(args).
+		File(output)
+
+	return report, nil
+}
+
+// check returns the JSON report for the target, failing if any finding
+// has one of the severities given by failOnSeverity.
+func (m *Trivy) check(ctx context.Context, t target) (*dagger.File, error) {
+	report, err := m.report(ctx, t, Json)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(m.FailOnSeverity) == 0 {
+		return report, nil
+	}
+
+	contents, err := report.Contents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	findings, err := parseFindings([]byte(contents))
+	if err != nil {
+		return nil, err
+	}
+
+	var failures []string
+	for _, f := range findings {
+		if !hasSeverity(m.FailOnSeverity, f.severity) {
+			continue
+		}
+
+		failures = append(failures, f.description)
+	}
+
+	if len(failures) > 0 {
+		return nil, fmt.Errorf(
+			"found %d finding(s) with severity %s:\n%s",
+			len(failures),
+			strings.Join(m.FailOnSeverity, ", "),
+			strings.Join(failures, "\n"),
+		)
+	}
+
+	return report, nil
+}
+
+// checkAndReport enforces the failure policy and then returns the report
+// in the requested format, reusing the JSON report when possible.
+func (m *Trivy) checkAndReport(ctx context.Context, t target, format ReportFormat) (*dagger.File, error) {
+	report, err := m.check(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == "" {
+		format = m.Format
+	}
+	if format == Json {
+		return report, nil
+	}
+
+	return m.report(ctx, t, format)
+}
+
+func hasSeverity(severities []string, severity string) bool {
+	for _, s := range severities {
+		if strings.EqualFold(s, severity) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,4 +1,4 @@
-module dagger/tests
+module dagger/trivy-tests
 
 go 1.24.4
 
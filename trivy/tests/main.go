@@ -1,4 +1,4 @@
-// A generated module for Tests functions
+// A generated module for TrivyTests functions
 //
 // This module has been generated via dagger init and serves as a reference to
 // basic module structure as you get started with Dagger.
@@ -16,26 +16,47 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 
-	"dagger/tests/internal/dagger"
+	"dagger/trivy-tests/internal/dagger"
 
 	"github.com/sourcegraph/conc/pool"
 )
 
-type Tests struct{}
+type TrivyTests struct{}
 
-func (m *Tests) All(ctx context.Context) error {
+func (m *TrivyTests) All(ctx context.Context) error {
 	ep := pool.New().WithErrors().WithContext(ctx)
 
 	ep.Go(m.GoApplicationTest)
+	ep.Go(m.ReportTest)
+	ep.Go(m.VulnerabilitiesTest)
+	ep.Go(m.FailOnSeverityTest)
+	ep.Go(m.ReportOnlyTest)
+	ep.Go(m.IgnoreFileTest)
+	ep.Go(m.IgnoreUnfixedTest)
+	ep.Go(m.ScanDirectoryTest)
+	ep.Go(m.ScanConfigTest)
+	ep.Go(m.ScanSbomTest)
+	ep.Go(m.ContainerSbomTest)
+	ep.Go(m.DirectorySbomTest)
+	ep.Go(m.OfflineScanTest)
+	ep.Go(m.OfflineScanConfigTest)
+	ep.Go(m.WithDbArchiveTest)
 
 	return ep.Wait()
 }
 
-func (m *Tests) GoApplicationTest(ctx context.Context) error {
-	app := dag.Go().Application(dag.CurrentModule().Source().Directory("testdata/goapp"), dagger.GoApplicationOpts{
-		ContainerScanner: dag.Trivy().AsGoContainerScanner(),
-	})
+func (m *TrivyTests) GoApplicationTest(ctx context.Context) error {
+	app := dag.Go().
+		Module(dag.CurrentModule().Source().Directory("testdata/goapp")).
+		Library().
+		Application(".", dagger.GoLibraryApplicationOpts{
+			ContainerScanner: dag.Trivy().AsGoContainerScanner(),
+		})
 
 	variants, err := app.Build(ctx)
 	if err != nil {
@@ -47,5 +68,344 @@ func (m *Tests) GoApplicationTest(ctx context.Context) error {
 		ctrs[i] = &variants[i]
 	}
 
-	return app.Scan(ctx, ctrs)
+	results, err := app.Scan(ctx, ctrs)
+	if err != nil {
+		return err
+	}
+
+	if len(results) != len(ctrs) {
+		return fmt.Errorf("expected a scan result per platform variant: %d != %d", len(results), len(ctrs))
+	}
+
+	for _, result := range results {
+		_, err := result.Report().Sync(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// vulnerableImage is old enough to be guaranteed known vulnerabilities.
+const vulnerableImage = "alpine:3.10.0"
+
+func (m *TrivyTests) ReportTest(ctx context.Context) error {
+	ctr := dag.Container().From(vulnerableImage)
+
+	formats := []dagger.TrivyReportFormat{
+		dagger.TrivyReportFormatTable,
+		dagger.TrivyReportFormatJson,
+		dagger.TrivyReportFormatSarif,
+		dagger.TrivyReportFormatCyclonedx,
+	}
+
+	ep := pool.New().WithErrors().WithContext(ctx)
+
+	for _, format := range formats {
+		ep.Go(func(ctx context.Context) error {
+			contents, err := dag.Trivy().Report(ctr, dagger.TrivyReportOpts{
+				Format: format,
+			}).Contents(ctx)
+			if err != nil {
+				return err
+			}
+
+			if strings.TrimSpace(contents) == "" {
+				return fmt.Errorf("expected non-empty %s report", format)
+			}
+
+			return nil
+		})
+	}
+
+	return ep.Wait()
+}
+
+func (m *TrivyTests) VulnerabilitiesTest(ctx context.Context) error {
+	trivy := dag.Trivy()
+
+	report := trivy.Report(dag.Container().From(vulnerableImage), dagger.TrivyReportOpts{
+		Format: dagger.TrivyReportFormatJson,
+	})
+
+	vulns, err := trivy.Vulnerabilities(ctx, report)
+	if err != nil {
+		return err
+	}
+
+	if len(vulns) == 0 {
+		return errors.New("expected vulnerabilities to be found")
+	}
+
+	id, err := vulns[0].VulnerabilityID(ctx)
+	if err != nil {
+		return err
+	}
+
+	pkg, err := vulns[0].Package(ctx)
+	if err != nil {
+		return err
+	}
+
+	severity, err := vulns[0].Severity(ctx)
+	if err != nil {
+		return err
+	}
+
+	if id == "" || pkg == "" || severity == "" {
+		return fmt.Errorf("expected vulnerability to be populated: id=%q package=%q severity=%q", id, pkg, severity)
+	}
+
+	return nil
+}
+
+func (m *TrivyTests) FailOnSeverityTest(ctx context.Context) error {
+	_, err := dag.Trivy().ScanContainer(dag.Container().From(vulnerableImage)).Sync(ctx)
+	if err == nil {
+		return errors.New("expected scan to fail on high or critical vulnerabilities")
+	}
+
+	return nil
+}
+
+func (m *TrivyTests) ReportOnlyTest(ctx context.Context) error {
+	_, err := dag.Trivy(dagger.TrivyOpts{
+		ReportOnly: true,
+	}).ScanContainer(dag.Container().From(vulnerableImage)).Sync(ctx)
+	return err
+}
+
+func (m *TrivyTests) IgnoreFileTest(ctx context.Context) error {
+	ctr := dag.Container().From(vulnerableImage)
+
+	trivy := dag.Trivy()
+
+	vulns, err := trivy.Vulnerabilities(ctx, trivy.Report(ctr, dagger.TrivyReportOpts{
+		Format: dagger.TrivyReportFormatJson,
+	}))
+	if err != nil {
+		return err
+	}
+
+	var ids []string
+	for _, vuln := range vulns {
+		id, err := vuln.VulnerabilityID(ctx)
+		if err != nil {
+			return err
+		}
+
+		ids = append(ids, id)
+	}
+
+	ignoreFile := dag.File(".trivyignore", strings.Join(ids, "\n"))
+
+	_, err = dag.Trivy(dagger.TrivyOpts{
+		IgnoreFile: ignoreFile,
+	}).ScanContainer(ctr).Sync(ctx)
+	return err
+}
+
+func (m *TrivyTests) IgnoreUnfixedTest(ctx context.Context) error {
+	trivy := dag.Trivy(dagger.TrivyOpts{
+		IgnoreUnfixed: true,
+	})
+
+	vulns, err := trivy.Vulnerabilities(ctx, trivy.Report(dag.Container().From(vulnerableImage), dagger.TrivyReportOpts{
+		Format: dagger.TrivyReportFormatJson,
+	}))
+	if err != nil {
+		return err
+	}
+
+	for _, vuln := range vulns {
+		fixed, err := vuln.FixedVersion(ctx)
+		if err != nil {
+			return err
+		}
+
+		if fixed == "" {
+			return errors.New("expected unfixed vulnerabilities to be ignored")
+		}
+	}
+
+	return nil
+}
+
+func (m *TrivyTests) ScanDirectoryTest(ctx context.Context) error {
+	trivy := dag.Trivy()
+
+	_, err := trivy.ScanDirectory(dag.CurrentModule().Source().Directory("testdata/goapp")).Sync(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = trivy.ScanDirectory(dag.CurrentModule().Source().Directory("testdata/vulnmod")).Sync(ctx)
+	if err == nil {
+		return errors.New("expected scan to fail on vulnerable golang.org/x/text dependency")
+	}
+
+	return nil
+}
+
+func (m *TrivyTests) ScanConfigTest(ctx context.Context) error {
+	trivy := dag.Trivy()
+
+	_, err := trivy.ScanConfig(dag.CurrentModule().Source().Directory("testdata/config")).Sync(ctx)
+	if err == nil {
+		return errors.New("expected scan to fail on Dockerfile running as root")
+	}
+
+	report, err := dag.Trivy(dagger.TrivyOpts{
+		ReportOnly: true,
+	}).ScanConfig(dag.CurrentModule().Source().Directory("testdata/config"), dagger.TrivyScanConfigOpts{
+		Format: dagger.TrivyReportFormatSarif,
+	}).Contents(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(report, "Dockerfile") {
+		return errors.New("expected sarif report to reference the Dockerfile")
+	}
+
+	return nil
+}
+
+func (m *TrivyTests) ScanSbomTest(ctx context.Context) error {
+	trivy := dag.Trivy()
+
+	sbom := trivy.Report(dag.Container().From(vulnerableImage), dagger.TrivyReportOpts{
+		Format: dagger.TrivyReportFormatCyclonedx,
+	})
+
+	_, err := trivy.ScanSbom(sbom).Sync(ctx)
+	if err == nil {
+		return errors.New("expected scan to fail on vulnerable sbom")
+	}
+
+	return nil
+}
+
+func (m *TrivyTests) ContainerSbomTest(ctx context.Context) error {
+	trivy := dag.Trivy()
+	ctr := dag.Container().From(vulnerableImage)
+
+	expected := map[string]string{
+		"cyclonedx": "CycloneDX",
+		"spdx":      "spdxVersion",
+	}
+	for format, marker := range expected {
+		sbom, err := trivy.ContainerSbom(ctr, format).Contents(ctx)
+		if err != nil {
+			return err
+		}
+
+		if !strings.Contains(sbom, marker) {
+			return fmt.Errorf("expected %s sbom to contain %s", format, marker)
+		}
+		if !strings.Contains(sbom, "musl") {
+			return fmt.Errorf("expected %s sbom to list the installed musl package", format)
+		}
+	}
+
+	_, err := trivy.ContainerSbom(ctr, "table").Sync(ctx)
+	if err == nil {
+		return errors.New("expected sbom generation to fail for an unsupported format")
+	}
+
+	return nil
+}
+
+func (m *TrivyTests) DirectorySbomTest(ctx context.Context) error {
+	sbom, err := dag.Trivy().DirectorySbom(dag.CurrentModule().Source().Directory("testdata/vulnmod"), "cyclonedx").Contents(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(sbom, "golang.org/x/text") {
+		return errors.New("expected sbom to list the golang.org/x/text dependency")
+	}
+
+	return nil
+}
+
+// Scanning with imported databases must not need to update them.
+func (m *TrivyTests) OfflineScanTest(ctx context.Context) error {
+	db := dag.Trivy().ExportDb()
+	javaDb := dag.Trivy().ExportJavaDb()
+
+	trivy := dag.Trivy(dagger.TrivyOpts{
+		Offline: true,
+	}).
+		WithDb(db).
+		WithJavaDb(javaDb)
+
+	vulns, err := trivy.Vulnerabilities(ctx, trivy.Report(dag.Container().From(vulnerableImage), dagger.TrivyReportOpts{
+		Format: dagger.TrivyReportFormatJson,
+	}))
+	if err != nil {
+		return err
+	}
+
+	if len(vulns) == 0 {
+		return errors.New("expected vulnerabilities to be found using the imported database")
+	}
+
+	return nil
+}
+
+// Scanning for misconfigurations with an imported checks bundle must not
+// need to update it.
+func (m *TrivyTests) OfflineScanConfigTest(ctx context.Context) error {
+	checks := dag.Trivy().ExportChecks()
+
+	entries, err := checks.Entries(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(entries, "metadata.json") {
+		return fmt.Errorf("expected exported checks bundle to contain metadata.json: %v", entries)
+	}
+
+	report, err := dag.Trivy(dagger.TrivyOpts{
+		Offline:    true,
+		ReportOnly: true,
+	}).
+		WithChecks(checks).
+		ScanConfig(dag.CurrentModule().Source().Directory("testdata/config"), dagger.TrivyScanConfigOpts{
+			Format: dagger.TrivyReportFormatSarif,
+		}).
+		Contents(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(report, "Dockerfile") {
+		return errors.New("expected sarif report to reference the Dockerfile")
+	}
+
+	return nil
+}
+
+func (m *TrivyTests) WithDbArchiveTest(ctx context.Context) error {
+	archive := dag.Archive().Tar().Create(dag.Trivy().ExportDb(), dagger.ArchiveTarCreateOpts{
+		Name:        "db.tar.gz",
+		Compression: dagger.ArchiveCompressionGzip,
+	})
+
+	trivy := dag.Trivy().WithDbArchive(archive)
+
+	entries, err := trivy.ExportDb().Entries(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(entries, "trivy.db") || !slices.Contains(entries, "metadata.json") {
+		return fmt.Errorf("expected imported database to contain trivy.db and metadata.json: %v", entries)
+	}
+
+	_, err = trivy.Report(dag.Container().From(vulnerableImage)).Sync(ctx)
+	return err
 }
@@ -0,0 +1,5 @@
+module vulnmod
+
+go 1.24.4
+
+require golang.org/x/text v0.3.0
@@ -0,0 +1,167 @@
+// Copyright (c) 2025 Z5Labs and Contributors
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"dagger/trivy/internal/dagger"
+)
+
+// Vulnerability
+type Vulnerability struct {
+	// Identifier of the vulnerability, e.g. CVE-2024-24790.
+	VulnerabilityID string
+
+	// Target containing the package, e.g. an OS or a binary.
+	Target string
+
+	// Name of the vulnerable package.
+	Package string
+
+	// Version of the package which was scanned.
+	InstalledVersion string
+
+	// Version(s) of the package fixing the vulnerability, if any.
+	FixedVersion string
+
+	// Severity of the vulnerability, e.g. LOW or CRITICAL.
+	Severity string
+
+	// Short description of the vulnerability.
+	Title string
+}
+
+// Format the vulnerability as a single line.
+func (v *Vulnerability) String() string {
+	fix := "no fix available"
+	if v.FixedVersion != "" {
+		fix = "fixed in " + v.FixedVersion
+	}
+
+	return fmt.Sprintf(
+		"%s: %s %s (%s, %s) in %s",
+		v.VulnerabilityID,
+		v.Package,
+		v.InstalledVersion,
+		v.Severity,
+		fix,
+		v.Target,
+	)
+}
+
+// Parse a JSON report returned by ScanContainer into individual vulnerabilities.
+func (m *Trivy) Vulnerabilities(
+	ctx context.Context,
+	report *dagger.File,
+) ([]*Vulnerability, error) {
+	contents, err := report.Contents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseVulnerabilities([]byte(contents))
+}
+
+func parseVulnerabilities(b []byte) ([]*Vulnerability, error) {
+	var report struct {
+		Results []struct {
+			Target          string
+			Vulnerabilities []struct {
+				VulnerabilityID  string
+				PkgName          string
+				InstalledVersion string
+				FixedVersion     string
+				Severity         string
+				Title            string
+			}
+		}
+	}
+	err := json.Unmarshal(b, &report)
+	if err != nil {
+		return nil, err
+	}
+
+	vulns := []*Vulnerability{}
+	for _, result := range report.Results {
+		for _, v := range result.Vulnerabilities {
+			vulns = append(vulns, &Vulnerability{
+				VulnerabilityID:  v.VulnerabilityID,
+				Target:           result.Target,
+				Package:          v.PkgName,
+				InstalledVersion: v.InstalledVersion,
+				FixedVersion:     v.FixedVersion,
+				Severity:         v.Severity,
+				Title:            v.Title,
+			})
+		}
+	}
+
+	return vulns, nil
+}
+
+// finding is any vulnerability, misconfiguration or secret reported by
+// trivy, reduced to what is needed to enforce the failure policy.
+type finding struct {
+	severity    string
+	description string
+}
+
+func parseFindings(b []byte) ([]finding, error) {
+	vulns, err := parseVulnerabilities(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var report struct {
+		Results []struct {
+			Target            string
+			Misconfigurations []struct {
+				ID       string
+				Title    string
+				Severity string
+			}
+			Secrets []struct {
+				RuleID    string
+				Title     string
+				Severity  string
+				StartLine int
+			}
+		}
+	}
+	err = json.Unmarshal(b, &report)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make([]finding, 0, len(vulns))
+	for _, v := range vulns {
+		findings = append(findings, finding{
+			severity:    v.Severity,
+			description: v.String(),
+		})
+	}
+
+	for _, result := range report.Results {
+		for _, m := range result.Misconfigurations {
+			findings = append(findings, finding{
+				severity:    m.Severity,
+				description: fmt.Sprintf("%s: %s (%s) in %s", m.ID, m.Title, m.Severity, result.Target),
+			})
+		}
+
+		for _, s := range result.Secrets {
+			findings = append(findings, finding{
+				severity:    s.Severity,
+				description: fmt.Sprintf("%s: %s (%s) in %s:%d", s.RuleID, s.Title, s.Severity, result.Target, s.StartLine),
+			})
+		}
+	}
+
+	return findings, nil
+}